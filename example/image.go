@@ -25,6 +25,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/llgcode/draw2d/draw2dimg"
@@ -34,10 +35,8 @@ import (
 	"image/color"
 	"image/draw"
 	"math"
-	"math/rand"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 )
 
@@ -162,34 +161,17 @@ func compareImage(img1 image.Image, img2 image.Image) float64 {
 		return difference
 	}
 
-	var wg sync.WaitGroup
-
-	mutex := &sync.Mutex{}
+	// the engine already evaluates chromosomes on a pool of workers, so there is no need to spawn more goroutines
 	result := 0.
 
 	width, height := img1.Bounds().Size().X, img1.Bounds().Size().Y
 
 	for i := 0; i < width; i++ {
-		wg.Add(1)
-
-		go func(i int) {
-			defer wg.Done()
-
-			difference := 0.
-			for j := 0; j < height; j++ {
-				difference += compare(img1.At(i, j), img2.At(i, j))
-			}
-
-			mutex.Lock()
-
-			result += difference
-
-			mutex.Unlock()
-		}(i)
+		for j := 0; j < height; j++ {
+			result += compare(img1.At(i, j), img2.At(i, j))
+		}
 	}
 
-	wg.Wait()
-
 	return result
 }
 
@@ -197,9 +179,11 @@ func main() {
 	var path string
 	var iterations int
 	var verbose bool
+	var checkpoint string
 
 	flag.IntVar(&iterations, "n", int(^uint(0)>>1), "number of iterations")
 	flag.BoolVar(&verbose, "v", false, "verbose")
+	flag.StringVar(&checkpoint, "c", "", "checkpoint file, the evolution is resumed from it if it exists")
 
 	flag.Usage = func() {
 		fmt.Println("Usage: image options target_image.png")
@@ -230,76 +214,97 @@ func main() {
 	shape := circle
 	width, height := sample.Bounds().Size().X, sample.Bounds().Size().Y
 
-	init := func(e *genetic.Engine) {
+	init := func(e *genetic.Engine[genetic.Chromosome]) {
 		for i := range e.Population {
-			for j := range e.Population[i].Chromosome.Genes {
-				e.Population[i].Chromosome.Genes[j].Randomize()
-				e.Population[i].Chromosome.Genes[j].Sequence[0] = .3 // hide circle
+			e.Population[i].Genome = genetic.NewChromosome(300, int(shape))
+
+			for j := range e.Population[i].Genome.Genes {
+				e.Population[i].Genome.Genes[j].Randomize(e.Rand())
+				e.Population[i].Genome.Genes[j].Sequence[0] = .3 // hide circle
 			}
 		}
 
 		for i := range e.Population {
-			j := rand.Intn(len(e.Population[i].Chromosome.Genes))
-			e.Population[i].Chromosome.Genes[j].Sequence[0] = .6
+			j := e.Rand().Intn(len(e.Population[i].Genome.Genes))
+			e.Population[i].Genome.Genes[j].Sequence[0] = .6
 		}
 	}
 
 	eval := func(chromosome genetic.Chromosome) float64 {
 		difference := compareImage(Picture{chromosome}.Draw(width, height, color.Black, shape), sample)
-		return 100 - (difference*100)/(float64(width)*float64(height)*255*255*4)
+		return (difference * 100) / (float64(width) * float64(height) * 255 * 255 * 4)
 	}
 
-	observer := func(i int, e *genetic.Engine) {
+	observer := func(i int, e *genetic.Engine[genetic.Chromosome]) {
 		if verbose {
-			best := e.Best()
+			stats := e.Stats()
 
 			n := 0
-			for _, g := range best.Genes {
+			for _, g := range e.Best().Genome.Genes {
 				if g.Sequence[0] > .5 {
 					n++
 				}
 			}
 
-			fmt.Printf("%d\t%f (%d-%d)\t%f\n", i, best.Fitness, best.Age, n, e.Worst().Fitness)
+			fmt.Printf("%d\t%f (%d)\t%f\t%f\tdiversity %f\tsurvivors %d\t%v\n", i, stats.Best, n, stats.Median,
+				stats.Worst, stats.Diversity, stats.Survivors, stats.Elapsed)
 		}
 
 		if i%100 == 0 {
-			img := Picture{e.Best().Chromosome}.Draw(4*width, 4*height, color.Black, shape)
+			img := Picture{e.Best().Genome}.Draw(4*width, 4*height, color.Black, shape)
 			draw2dimg.SaveToPngFile("monna-lisa.png", img)
 		}
 	}
 
-	configuration := genetic.Configuration{
-		GeneLength:       int(shape),
-		ChromosomeLength: 300,
-		PopulationSize:   100,
-		MaxAge:           3,
-		Selection:        genetic.TournamentSelection{10},
-		Crossover:        genetic.UniformCrossover{},
-		Mutation:         genetic.Gaussian{.001, .1, 0.},
-		Elitism:          .1,
-		Iterations:       iterations,
-		Init:             init,
-		Evaluator:        eval,
-		Observer:         observer,
+	configuration := genetic.Configuration[genetic.Chromosome]{
+		PopulationSize: 100,
+		MaxAge:         3,
+		Direction:      genetic.Minimize,
+		Selection:      genetic.TournamentSelection{10},
+		Crossover:      genetic.UniformCrossover{},
+		Mutation:       genetic.Gaussian{.001, .1, 0.},
+		Elitism:        .1,
+		Iterations:     iterations,
+		Init:           init,
+		Evaluator:      eval,
+		Observer:       observer,
+		HistorySize:    1000,
+		Cache:          genetic.NewCache[genetic.Chromosome](1000),
 	}
 
-	engine := genetic.Engine{Configuration: configuration}
+	if checkpoint != "" {
+		configuration.Checkpoint = genetic.Checkpoint{Path: checkpoint, Interval: 100}
+	}
+
+	engine := genetic.Engine[genetic.Chromosome]{Configuration: configuration}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		<-c
-		engine.Stop()
+		cancel()
 	}()
 
-	best, elapsed := engine.Start()
+	var result genetic.Result[genetic.Chromosome]
+
+	if snapshot, e := genetic.LoadSnapshot[genetic.Chromosome](checkpoint); e == nil {
+		result, err = engine.Resume(ctx, snapshot)
+	} else {
+		result, err = engine.Run(ctx)
+	}
+
+	if err != nil {
+		panic(err)
+	}
 
 	if verbose {
-		fmt.Printf("Completed in %v\n", elapsed)
+		fmt.Printf("Completed in %v (%v after %d generations)\n", result.Elapsed, result.Reason, result.Generations)
 	}
 
-	img := Picture{best.Chromosome}.Draw(4*width, 4*height, color.Black, shape)
+	img := Picture{result.Best.Genome}.Draw(4*width, 4*height, color.Black, shape)
 	draw2dimg.SaveToPngFile("monna-lisa.png", img)
 }
@@ -13,7 +13,6 @@ import (
 	"image/png"
 	"log"
 	"math"
-	"math/rand"
 	"net/http"
 	"os"
 	"os/exec"
@@ -99,34 +98,17 @@ func compareImage(img1 image.Image, img2 image.Image) float64 {
 		return difference
 	}
 
-	var wg sync.WaitGroup
-
-	mutex := &sync.Mutex{}
+	// the engine already evaluates chromosomes on a pool of workers, so there is no need to spawn more goroutines
 	result := 0.
 
 	width, height := img1.Bounds().Size().X, img1.Bounds().Size().Y
 
 	for i := 0; i < width; i++ {
-		wg.Add(1)
-
-		go func(i int) {
-			defer wg.Done()
-
-			difference := 0.
-			for j := 0; j < height; j++ {
-				difference += compare(img1.At(i, j), img2.At(i, j))
-			}
-
-			mutex.Lock()
-
-			result += difference
-
-			mutex.Unlock()
-		}(i)
+		for j := 0; j < height; j++ {
+			result += compare(img1.At(i, j), img2.At(i, j))
+		}
 	}
 
-	wg.Wait()
-
 	return result
 }
 
@@ -134,28 +116,30 @@ type Evolution struct {
 	sample image.Image
 	running bool
 	mutex sync.Mutex
-	engine genetic.Engine
-	result genetic.Phenotype
+	engine genetic.Engine[genetic.Chromosome]
+	result genetic.Phenotype[genetic.Chromosome]
 }
 
 func newEvolution(img image.Image) *Evolution {
 	ev := Evolution{
 		sample: img,
 		running: false,
-		engine: genetic.Engine{},
+		engine: genetic.Engine[genetic.Chromosome]{},
 	}
 
-	init := func(e *genetic.Engine) {
+	init := func(e *genetic.Engine[genetic.Chromosome]) {
 		for i := range e.Population {
-			for j := range e.Population[i].Chromosome.Genes {
-				e.Population[i].Chromosome.Genes[j].Randomize()
-				e.Population[i].Chromosome.Genes[j].Sequence[0] = .3 // hide
+			e.Population[i].Genome = genetic.NewChromosome(150, int(circle))
+
+			for j := range e.Population[i].Genome.Genes {
+				e.Population[i].Genome.Genes[j].Randomize(e.Rand())
+				e.Population[i].Genome.Genes[j].Sequence[0] = .3 // hide
 			}
 		}
 
 		for i := range e.Population {
-			j := rand.Intn(len(e.Population[i].Chromosome.Genes))
-			e.Population[i].Chromosome.Genes[j].Sequence[0] = .6
+			j := e.Rand().Intn(len(e.Population[i].Genome.Genes))
+			e.Population[i].Genome.Genes[j].Sequence[0] = .6
 		}
 	}
 
@@ -164,7 +148,7 @@ func newEvolution(img image.Image) *Evolution {
 		return 100 - (difference*100)/(float64(img.Bounds().Size().X)*float64(img.Bounds().Size().Y)*255*255*4)
 	}
 
-	observer := func(i int, e *genetic.Engine) {
+	observer := func(i int, e *genetic.Engine[genetic.Chromosome]) {
 		best := e.Best()
 
 		if best.Fitness > ev.result.Fitness {
@@ -172,19 +156,17 @@ func newEvolution(img image.Image) *Evolution {
 		}
 	}
 
-	ev.engine.Configuration = genetic.Configuration{
-		GeneLength:       int(circle),
-		ChromosomeLength: 150,
-		PopulationSize:   75,
-		MaxAge:           5,
-		Selection:        genetic.TournamentSelection{Size: 10},
-		Crossover:        genetic.UniformCrossover{},
-		Mutation:         genetic.Gaussian{Probability: .001, Std: .1, Mean: 0.},
-		Elitism:          .1,
-		Iterations:       int(^uint(0) >> 1), // max int
-		Init:             init,
-		Evaluator:        eval,
-		Observer:         observer,
+	ev.engine.Configuration = genetic.Configuration[genetic.Chromosome]{
+		PopulationSize: 75,
+		MaxAge:         5,
+		Selection:      genetic.TournamentSelection{Size: 10},
+		Crossover:      genetic.UniformCrossover{},
+		Mutation:       genetic.Gaussian{Probability: .001, Std: .1, Mean: 0.},
+		Elitism:        .1,
+		Iterations:     int(^uint(0) >> 1), // max int
+		Init:           init,
+		Evaluator:      eval,
+		Observer:       observer,
 	}
 
 	return &ev
@@ -218,7 +200,7 @@ func (ev *Evolution) stop() error {
 	return nil
 }
 
-func (ev *Evolution) best() (*genetic.Phenotype, error) {
+func (ev *Evolution) best() (*genetic.Phenotype[genetic.Chromosome], error) {
 	ev.mutex.Lock()
 	defer ev.mutex.Unlock()
 
@@ -320,7 +302,7 @@ func main() {
 			return
 		}
 
-		img := Picture{Chromosome: p.Chromosome}.Draw(250, 250, color.Black)
+		img := Picture{Chromosome: p.Genome}.Draw(250, 250, color.Black)
 
 		if err := png.Encode(w, img); err != nil {
 			fmt.Println(err)
@@ -0,0 +1,120 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/marcopacini/go-genetic/genetic"
+	"github.com/marcopacini/go-genetic/genetic/gp"
+	"github.com/marcopacini/go-genetic/genetic/gp/regression"
+	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	var target string
+	var held float64
+	var iterations, population int
+	var parsimony float64
+	var seed int64
+	var verbose bool
+
+	flag.StringVar(&target, "t", "", "target column, the last one by default")
+	flag.Float64Var(&held, "h", .2, "fraction of held-out samples")
+	flag.IntVar(&iterations, "n", 200, "number of iterations")
+	flag.IntVar(&population, "p", 500, "population size")
+	flag.Float64Var(&parsimony, "s", 0, "parsimony coefficient, the penalty of every node")
+	flag.Int64Var(&seed, "seed", 1, "random seed")
+	flag.BoolVar(&verbose, "v", false, "verbose")
+
+	flag.Usage = func() {
+		fmt.Println("Usage: regression options dataset.csv")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if len(flag.Args()) != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	dataset, err := regression.LoadCSV(flag.Args()[0], target)
+	if err != nil {
+		panic(err)
+	}
+
+	train, test := dataset.Split(held, rand.New(rand.NewSource(seed)))
+
+	observer := func(i int, e *genetic.Engine[gp.Tree]) {
+		if verbose {
+			stats := e.Stats()
+			fmt.Printf("%d\t%f\t%f\tsize %d\n", i, stats.Best, stats.Median, e.Best().Genome.Size())
+		}
+	}
+
+	r := &regression.Regression{
+		Configuration: genetic.Configuration[gp.Tree]{
+			PopulationSize: population,
+			MaxAge:         10,
+			Elitism:        .05,
+			Iterations:     iterations,
+			Observer:       observer,
+			Terminator:     genetic.TargetFitness{Fitness: 1e-12},
+			Cache:          genetic.NewCache[gp.Tree](10 * population),
+			Seed:           seed,
+		},
+		Functions: append(gp.Arithmetic(), gp.Sin, gp.Cos, gp.Log),
+		Constant:  gp.UniformConstant(-5, 5),
+		Parsimony: parsimony,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	report, err := r.Run(ctx, train, test)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Completed in %v (%v after %d generations)\n", report.Result.Elapsed, report.Result.Reason,
+		report.Result.Generations)
+	fmt.Printf("train\tMSE %g\tR2 %f\t(%d samples)\n", report.Train.MSE, report.Train.R2, train.Len())
+	fmt.Printf("test\tMSE %g\tR2 %f\t(%d samples)\n\n", report.Test.MSE, report.Test.R2, test.Len())
+	fmt.Println(report.Go("f"))
+	fmt.Printf("$%s$\n", report.LaTeX())
+}
@@ -65,10 +65,12 @@ func distance(s1, s2 string) (int, error) {
 func main() {
 	const text = "Hello, World!"
 
-	init := func (e *genetic.Engine) {
+	init := func (e *genetic.Engine[genetic.Chromosome]) {
 		for i, _ := range e.Population {
-			for j, _ := range e.Population[i].Chromosome.Genes {
-				e.Population[i].Chromosome.Genes[j].Randomize()
+			e.Population[i].Genome = genetic.NewChromosome(len(text), 1)
+
+			for j, _ := range e.Population[i].Genome.Genes {
+				e.Population[i].Genome.Genes[j].Randomize(e.Rand())
 			}
 		}
 	}
@@ -81,30 +83,22 @@ func main() {
 		return 0.
 	}
 
-	observer := func(i int, e *genetic.Engine) {
-		if int(e.Best().Fitness) == len(text) {
-			e.Stop()
-		}
-	}
-
-	configuration := genetic.Configuration{
-		GeneLength:       1,
-		ChromosomeLength: len(text),
-		PopulationSize:   150,
-		MaxAge:			  25,
-		Selection:        genetic.ElitismSelection{.1},
-		Crossover:        genetic.SinglePointCrossover{},
-		Mutation:         genetic.Uniform{.01},
-		Elitism:          .15,
-		Iterations:       1000,
-		Init: 			  init,
-		Evaluator:        eval,
-		Observer:         observer,
+	configuration := genetic.Configuration[genetic.Chromosome]{
+		PopulationSize: 150,
+		MaxAge:         25,
+		Selection:      genetic.ElitismSelection{.1},
+		Crossover:      genetic.SinglePointCrossover{},
+		Mutation:       genetic.Uniform{.01},
+		Elitism:        .15,
+		Iterations:     1000,
+		Init:           init,
+		Evaluator:      eval,
+		Terminator:     genetic.TargetFitness{Fitness: float64(len(text))},
 	}
 
-	engine := genetic.Engine{Configuration: configuration}
+	engine := genetic.Engine[genetic.Chromosome]{Configuration: configuration}
 	best, elapsed := engine.Start()
 
 	fmt.Printf("Completed in %v\n", elapsed)
-	fmt.Printf("%v\t{%v/%v}\n", Text{best.Chromosome, ""}, best.Fitness, len(text))
+	fmt.Printf("%v\t{%v/%v}\n", Text{best.Genome, ""}, best.Fitness, len(text))
 }
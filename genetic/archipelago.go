@@ -0,0 +1,294 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A Topology tells to which islands the emigrants of an island move
+type Topology interface {
+	Destinations(island int, islands int, r *rand.Rand) []int
+}
+
+// Ring moves the emigrants of each island to the next one
+type Ring struct{}
+
+func (Ring) Destinations(island int, islands int, r *rand.Rand) []int {
+	if islands < 2 {
+		return nil
+	}
+
+	return []int{(island + 1) % islands}
+}
+
+// FullyConnected moves the emigrants of each island to every other island
+type FullyConnected struct{}
+
+func (FullyConnected) Destinations(island int, islands int, r *rand.Rand) []int {
+	destinations := make([]int, 0, islands-1)
+
+	for i := 0; i < islands; i++ {
+		if i != island {
+			destinations = append(destinations, i)
+		}
+	}
+
+	return destinations
+}
+
+// RandomTopology moves the emigrants of each island to Neighbours other islands drawn at every migration
+type RandomTopology struct {
+	Neighbours int
+}
+
+func (t RandomTopology) Destinations(island int, islands int, r *rand.Rand) []int {
+	destinations := FullyConnected{}.Destinations(island, islands, r)
+
+	r.Shuffle(len(destinations), func(i, j int) {
+		destinations[i], destinations[j] = destinations[j], destinations[i]
+	})
+
+	if t.Neighbours < len(destinations) {
+		destinations = destinations[:t.Neighbours]
+	}
+
+	return destinations
+}
+
+// Migration describes how individuals move among islands: every Interval generations the best Size individuals of
+// each island replace the worst ones of its destinations
+type Migration struct {
+	Interval int
+	Size     int
+	Topology Topology
+}
+
+// An Archipelago runs several engines (islands) concurrently, periodically migrating individuals among them. Islands
+// can have different configurations, but they must share Direction and genome type. Each island ends according to
+// its own configuration, the archipelago ends when all the islands have ended
+type Archipelago[G Genome[G]] struct {
+	Islands []*Engine[G]
+	Migration
+	// Seed of the random generator driving the topology, if zero it is drawn from the current time
+	Seed int64
+}
+
+// ArchipelagoResult collects the result of every island
+type ArchipelagoResult[G any] struct {
+	Best       Phenotype[G]
+	Islands    []Result[G]
+	Migrations int
+	Elapsed    time.Duration
+}
+
+// Validate returns an error describing the first invalid island or migration parameter, if any
+func (a *Archipelago[G]) Validate() error {
+	if len(a.Islands) == 0 {
+		return errors.New("missing islands")
+	}
+
+	if a.Interval < 0 {
+		return fmt.Errorf("invalid migration interval: %d (must not be negative)", a.Interval)
+	}
+
+	if a.Interval > 0 && a.Topology == nil {
+		return errors.New("missing migration topology")
+	}
+
+	for i, island := range a.Islands {
+		if err := island.Validate(); err != nil {
+			return fmt.Errorf("island %d: %w", i, err)
+		}
+
+		if a.Size < 0 || a.Size > island.PopulationSize {
+			return fmt.Errorf("invalid migration size: %d (must be in [0, %d])", a.Size, island.PopulationSize)
+		}
+
+		// migration and the best of the archipelago compare individuals of different islands
+		if island.Direction != a.Islands[0].Direction {
+			return fmt.Errorf("island %d: invalid direction: %v (must be the same of island 0)", i, island.Direction)
+		}
+	}
+
+	return nil
+}
+
+// Run evolves all the islands until each of them has ended. An error occurred in an island ends the whole run
+func (a *Archipelago[G]) Run(ctx context.Context) (ArchipelagoResult[G], error) {
+	if err := a.Validate(); err != nil {
+		return ArchipelagoResult[G]{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	start := time.Now()
+
+	seed := a.Seed
+	if seed == 0 {
+		seed = start.UnixNano()
+	}
+
+	migrations := 0
+
+	err := a.parallel(func(island *Engine[G]) error {
+		return island.initialize(ctx)
+	})
+
+	// without migrations every island runs to its end in one go
+	interval := a.Interval
+	if interval == 0 {
+		interval = int(^uint(0) >> 1)
+	}
+
+	for err == nil {
+		if err = a.parallel(func(island *Engine[G]) error {
+			return island.evolve(ctx, interval)
+		}); err != nil {
+			break
+		}
+
+		if a.done(ctx) {
+			break
+		}
+
+		a.migrate(derive(seed, migrations))
+		migrations++
+	}
+
+	// an island still running when another one failed may have evaluations in flight
+	for _, island := range a.Islands {
+		island.drain()
+	}
+
+	result := ArchipelagoResult[G]{
+		Islands:    make([]Result[G], len(a.Islands)),
+		Migrations: migrations,
+		Elapsed:    time.Since(start),
+	}
+
+	for i, island := range a.Islands {
+		result.Islands[i] = island.result()
+
+		if i == 0 || island.Direction.Better(result.Islands[i].Best.Fitness, result.Best.Fitness) {
+			result.Best = result.Islands[i].Best
+		}
+	}
+
+	return result, err
+}
+
+// Stop stops all the islands
+func (a *Archipelago[G]) Stop() {
+	for _, island := range a.Islands {
+		island.Stop()
+	}
+}
+
+// parallel runs f on every island that has not ended yet, returning the first error
+func (a *Archipelago[G]) parallel(f func(*Engine[G]) error) error {
+	var wg sync.WaitGroup
+
+	errs := make([]error, len(a.Islands))
+
+	for i, island := range a.Islands {
+		if island.finished {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, island *Engine[G]) {
+			defer wg.Done()
+			errs[i] = f(island)
+		}(i, island)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("island %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// done reports whether all the islands have ended
+func (a *Archipelago[G]) done(ctx context.Context) bool {
+	done := true
+
+	for _, island := range a.Islands {
+		if !island.done(ctx) {
+			done = false
+		}
+	}
+
+	return done
+}
+
+// migrate moves the emigrants of every island to its destinations. Emigrants are chosen before any island receives
+// immigrants, so the outcome does not depend on the order of the islands
+func (a *Archipelago[G]) migrate(r *rand.Rand) {
+	immigrants := make([][]Phenotype[G], len(a.Islands))
+
+	for i, island := range a.Islands {
+		island.rank()
+
+		for _, j := range a.Topology.Destinations(i, len(a.Islands), r) {
+			for _, emigrant := range island.Population[:a.Size] {
+				emigrant.Genome = emigrant.Genome.Clone()
+				immigrants[j] = append(immigrants[j], emigrant)
+			}
+		}
+	}
+
+	for i, island := range a.Islands {
+		if !island.finished {
+			island.immigrate(immigrants[i])
+		}
+	}
+}
+
+// immigrate replaces the worst individuals of the population with immigrants
+func (e *Engine[G]) immigrate(immigrants []Phenotype[G]) {
+	if len(immigrants) > len(e.Population) {
+		immigrants = immigrants[:len(e.Population)]
+	}
+
+	copy(e.Population[len(e.Population)-len(immigrants):], immigrants)
+	e.rank()
+
+	// the parents of the children in flight cannot be tracked across the ranking
+	if e.pipeline != nil {
+		for id := range e.pipeline.parents {
+			e.pipeline.parents[id] = nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package genetic
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTopology_Destinations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	if got, want := (Ring{}).Destinations(3, 4, r), []int{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ring.Destinations(3, 4) = %v, want %v", got, want)
+	}
+
+	if got, want := (FullyConnected{}).Destinations(1, 4, r), []int{0, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FullyConnected.Destinations(1, 4) = %v, want %v", got, want)
+	}
+
+	for _, d := range (RandomTopology{Neighbours: 2}).Destinations(1, 4, r) {
+		if d == 1 || d < 0 || d > 3 {
+			t.Errorf("RandomTopology.Destinations(1, 4) contains %d", d)
+		}
+	}
+}
+
+func TestArchipelago_Run(t *testing.T) {
+	archipelago := Archipelago[Chromosome]{
+		Islands:   []*Engine[Chromosome]{newTestEngine(), newTestEngine(), newTestEngine()},
+		Migration: Migration{Interval: 5, Size: 2, Topology: Ring{}},
+	}
+
+	archipelago.Islands[1].Iterations = 20
+
+	result, err := archipelago.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Migrations != 9 {
+		t.Errorf("result.Migrations = %d, want 9", result.Migrations)
+	}
+
+	for i, want := range []int{50, 20, 50} {
+		if result.Islands[i].Generations != want {
+			t.Errorf("result.Islands[%d].Generations = %d, want %d", i, result.Islands[i].Generations, want)
+		}
+
+		if result.Islands[i].Best.Fitness > result.Best.Fitness {
+			t.Errorf("result.Islands[%d].Best.Fitness = %f > result.Best.Fitness = %f", i,
+				result.Islands[i].Best.Fitness, result.Best.Fitness)
+		}
+	}
+}
+
+func TestArchipelago_Validate(t *testing.T) {
+	archipelago := Archipelago[Chromosome]{
+		Islands:   []*Engine[Chromosome]{newTestEngine()},
+		Migration: Migration{Interval: 5, Size: 2},
+	}
+
+	if err := archipelago.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error")
+	}
+
+	archipelago.Islands = []*Engine[Chromosome]{newTestEngine(), newTestEngine()}
+	archipelago.Topology = Ring{}
+
+	if err := archipelago.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	archipelago.Islands[1].Direction = Minimize
+
+	if err := archipelago.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for islands with different directions")
+	}
+}
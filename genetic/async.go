@@ -0,0 +1,172 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"context"
+	"sync"
+)
+
+// pipeline holds the evaluations of asynchronous evolution, they stay in flight across generations
+type pipeline[G any] struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results chan evaluation[G]
+	// parents holds the indexes of the parents of every child being evaluated, kept up to date with replacements
+	parents map[int][]int
+	next    int
+	group   sync.WaitGroup
+}
+
+// evaluation is the outcome of an asynchronous evaluation of a child, id identifies its parents in the pipeline
+type evaluation[G any] struct {
+	id    int
+	child Phenotype[G]
+	err   error
+}
+
+// async runs a generation of asynchronous steady-state evolution: InFlight evaluations are kept running and every
+// child replaces an individual as soon as its evaluation completes, so a slow evaluation does not stall the others.
+// Unlike steady, it works on the population in place and the outcome depends on the order in which evaluations
+// complete, so runs are not reproducible
+func (e *Engine[G]) async(ctx context.Context) ([]Phenotype[G], error) {
+	if e.pipeline == nil {
+		e.rank()
+
+		// dispatch overshoots InFlight by less than a crossover, so evaluations never block on sending their result
+		p := &pipeline[G]{
+			results: make(chan evaluation[G], e.InFlight+e.Crossover.Children()),
+			parents: make(map[int][]int),
+		}
+		p.ctx, p.cancel = context.WithCancel(ctx)
+		e.pipeline = p
+	}
+
+	p := e.pipeline
+	population := e.Population
+
+	for i := range population {
+		population[i].Age++
+	}
+
+	scores := scores(population)
+
+	steps := e.Replacements
+	if steps == 0 {
+		steps = e.PopulationSize
+	}
+
+	for step := 0; step < steps; step++ {
+		for len(p.parents) < e.InFlight {
+			if err := e.dispatch(population, scores); err != nil {
+				return nil, err
+			}
+		}
+
+		var result evaluation[G]
+
+		select {
+		case result = <-p.results:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		parents := p.parents[result.id]
+		delete(p.parents, result.id)
+
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		i, err := e.replacement(scores, parents, result.child.Score, e.random)
+		if err != nil {
+			return nil, err
+		}
+
+		if i >= 0 {
+			// the parents of the children still in flight may be moved, or replaced
+			j := e.replace(population, scores, i, result.child)
+
+			for id, indexes := range p.parents {
+				p.parents[id] = shift(indexes, i, j)
+			}
+		}
+	}
+
+	return population, nil
+}
+
+// dispatch breeds children of population, whose scores are scores, and starts their evaluations
+func (e *Engine[G]) dispatch(population []Phenotype[G], scores []Score) error {
+	p := e.pipeline
+
+	var parents []int
+	var children []G
+
+	err := safely(func() (err error) {
+		parents, children, err = e.reproduce(population, e.prepare(scores), scores, e.random)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		id := p.next
+		p.next++
+
+		// every child gets its own copy, since shift updates them in place
+		p.parents[id] = append([]int(nil), parents...)
+		p.group.Add(1)
+
+		go func(child G) {
+			defer p.group.Done()
+
+			result := evaluation[G]{id: id}
+
+			phenotypes, err := e.evaluateAll(p.ctx, []G{child})
+			if err != nil {
+				result.err = err
+			} else {
+				result.child = phenotypes[0]
+			}
+
+			p.results <- result
+		}(child)
+	}
+
+	return nil
+}
+
+// drain cancels the evaluations in flight, if any, and waits for them to return discarding their results
+func (e *Engine[G]) drain() {
+	if e.pipeline == nil {
+		return
+	}
+
+	e.pipeline.cancel()
+	e.pipeline.group.Wait()
+	e.pipeline = nil
+}
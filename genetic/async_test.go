@@ -0,0 +1,119 @@
+package genetic
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEngine_RunAsync(t *testing.T) {
+	engine := newTestEngine()
+	engine.Replacement = ReplaceWorst{}
+	engine.InFlight = 8
+	engine.Iterations = 10
+
+	var running, peak int64
+
+	// evaluations of very different latency, the slow ones must not stall the others
+	engine.ContextEvaluator = func(ctx context.Context, c Chromosome) (float64, error) {
+		n := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+
+		for {
+			p := atomic.LoadInt64(&peak)
+			if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+				break
+			}
+		}
+
+		delay := time.Duration(c.Genes[0].Sequence[0]*1000) * time.Microsecond
+
+		select {
+		case <-ctx.Done():
+			return 0., ctx.Err()
+		case <-time.After(delay):
+			return sum(c), nil
+		}
+	}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generations != engine.Iterations {
+		t.Errorf("result.Generations = %d, want %d", result.Generations, engine.Iterations)
+	}
+
+	if peak > int64(engine.InFlight+engine.Crossover.Children()) {
+		t.Errorf("peak = %d, want <= %d", peak, engine.InFlight+engine.Crossover.Children())
+	}
+
+	if running != 0 {
+		t.Errorf("running = %d, want 0 after Run", running)
+	}
+
+	sorted := sort.SliceIsSorted(engine.Population, func(i, j int) bool {
+		return engine.Population[i].Fitness > engine.Population[j].Fitness
+	})
+
+	if !sorted {
+		t.Errorf("engine.Population is not sorted")
+	}
+
+	for _, phenotype := range engine.Population {
+		if phenotype.Fitness != sum(phenotype.Genome) {
+			t.Errorf("phenotype.Fitness = %f, want %f", phenotype.Fitness, sum(phenotype.Genome))
+		}
+	}
+}
+
+func TestEngine_RunAsyncCanceled(t *testing.T) {
+	engine := newTestEngine()
+	engine.Replacement = ReplaceWorst{}
+	engine.InFlight = 4
+	engine.Iterations = int(^uint(0) >> 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine.Observer = func(i int, e *Engine[Chromosome]) {
+		if i == 4 {
+			cancel()
+		}
+	}
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Canceled {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Canceled)
+	}
+
+	if engine.pipeline != nil {
+		t.Errorf("engine.pipeline = %v, want nil", engine.pipeline)
+	}
+}
+
+func TestEngine_RunAsyncPanic(t *testing.T) {
+	engine := newTestEngine()
+	engine.Replacement = ReplaceWorst{}
+	engine.InFlight = 4
+	engine.Mutation = panickingMutator{}
+
+	if _, err := engine.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("Run() error = %v, want panic: boom", err)
+	}
+
+	engine = newTestEngine()
+	engine.Replacement = panickingReplacement{}
+	engine.InFlight = 4
+
+	if _, err := engine.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("Run() error = %v, want panic: boom", err)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// A BatchEvaluator scores many genomes at once, returning their fitness in the same order
+type BatchEvaluator[G any] interface {
+	EvaluateBatch(context.Context, []G) ([]float64, error)
+}
+
+// BatchEvaluatorFunc adapts a function to the BatchEvaluator interface
+type BatchEvaluatorFunc[G any] func(context.Context, []G) ([]float64, error)
+
+func (f BatchEvaluatorFunc[G]) EvaluateBatch(ctx context.Context, genomes []G) ([]float64, error) {
+	return f(ctx, genomes)
+}
+
+// evaluateBatch scores genomes with a single call to the batch evaluator. Genomes found in the cache, and duplicates
+// of another genome of the batch, are not passed to the evaluator
+func (e *Engine[G]) evaluateBatch(ctx context.Context, genomes []G) ([]Phenotype[G], error) {
+	phenotypes := make([]Phenotype[G], len(genomes))
+
+	// source[i] is the index within batch of the genome giving its score to phenotypes[i], -1 if cached
+	source := make([]int, len(genomes))
+	batch := make([]G, 0, len(genomes))
+	pending := make(map[uint64][]int)
+
+	for i, genome := range genomes {
+		phenotypes[i].Genome = genome
+		source[i] = -1
+
+		if e.Cache == nil {
+			source[i] = len(batch)
+			batch = append(batch, genome)
+			continue
+		}
+
+		if value, ok := e.Cache.get(genome); ok {
+			phenotypes[i].Fitness = value.fitness
+			continue
+		}
+
+		key := e.Cache.hash(genome)
+
+		for _, j := range pending[key] {
+			if e.Cache.equal(batch[j], genome) {
+				source[i] = j
+				break
+			}
+		}
+
+		if source[i] < 0 {
+			source[i] = len(batch)
+			pending[key] = append(pending[key], len(batch))
+			batch = append(batch, genome)
+		}
+	}
+
+	if len(batch) == 0 {
+		return phenotypes, nil
+	}
+
+	fitness, err := e.scoreBatch(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("evaluation: %w", err)
+	}
+
+	if len(fitness) != len(batch) {
+		return nil, fmt.Errorf("evaluation: invalid batch result size: %d != %d", len(fitness), len(batch))
+	}
+
+	for i := range phenotypes {
+		if source[i] >= 0 {
+			phenotypes[i].Fitness = fitness[source[i]]
+		}
+	}
+
+	if e.Cache != nil {
+		for i, genome := range batch {
+			e.Cache.put(genome, score{fitness: fitness[i]})
+		}
+	}
+
+	return phenotypes, nil
+}
+
+// scoreBatch calls the batch evaluator, a panicking evaluator is reported as an error
+func (e *Engine[G]) scoreBatch(ctx context.Context, genomes []G) (fitness []float64, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	atomic.AddInt64(&e.evaluations, int64(len(genomes)))
+
+	return e.BatchEvaluator.EvaluateBatch(ctx, genomes)
+}
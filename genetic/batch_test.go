@@ -0,0 +1,77 @@
+package genetic
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEngine_RunBatch(t *testing.T) {
+	calls, evaluated := 0, 0
+
+	engine := newTestEngine()
+	engine.BatchEvaluator = BatchEvaluatorFunc[Chromosome](func(ctx context.Context, chromosomes []Chromosome) ([]float64, error) {
+		calls++
+		evaluated += len(chromosomes)
+
+		fitness := make([]float64, len(chromosomes))
+
+		for i := range chromosomes {
+			fitness[i] = sum(chromosomes[i])
+		}
+
+		return fitness, nil
+	})
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != engine.Iterations+1 {
+		t.Errorf("calls = %d, want %d", calls, engine.Iterations+1)
+	}
+
+	if evaluated != engine.Evaluations() {
+		t.Errorf("evaluated = %d, want %d", evaluated, engine.Evaluations())
+	}
+
+	if result.Best.Fitness != sum(result.Best.Genome) {
+		t.Errorf("result.Best.Fitness = %f, want %f", result.Best.Fitness, sum(result.Best.Genome))
+	}
+}
+
+func TestEngine_RunBatchCache(t *testing.T) {
+	engine := newTestEngine()
+	engine.Crossover = None[Chromosome]{}
+	engine.Mutation = Uniform{Probability: 0}
+	engine.Cache = NewCache[Chromosome](100)
+	engine.BatchEvaluator = BatchEvaluatorFunc[Chromosome](func(ctx context.Context, chromosomes []Chromosome) ([]float64, error) {
+		fitness := make([]float64, len(chromosomes))
+
+		for i := range chromosomes {
+			fitness[i] = sum(chromosomes[i])
+		}
+
+		return fitness, nil
+	})
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if engine.Evaluations() != engine.PopulationSize {
+		t.Errorf("engine.Evaluations() = %d, want %d", engine.Evaluations(), engine.PopulationSize)
+	}
+}
+
+func TestEngine_RunBatchError(t *testing.T) {
+	engine := newTestEngine()
+	engine.BatchEvaluator = BatchEvaluatorFunc[Chromosome](func(ctx context.Context, chromosomes []Chromosome) ([]float64, error) {
+		return nil, errors.New("failure")
+	})
+
+	if _, err := engine.Run(context.Background()); err == nil {
+		t.Errorf("Run() error = nil, want error")
+	}
+}
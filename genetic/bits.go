@@ -0,0 +1,378 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Bits is a fixed-length bit string packed in 64-bit words, as used by classic binary genetic algorithms
+type Bits struct {
+	words []uint64
+	n     int
+}
+
+// NewBits returns a bit string of length n with all the bits unset
+func NewBits(n int) Bits {
+	if n < 0 {
+		panic(fmt.Sprintf("invalid argument: n = %d (must not be negative)", n))
+	}
+
+	return Bits{make([]uint64, (n+63)/64), n}
+}
+
+// RandomBits returns a bit string of length n whose bits are drawn uniformly using r
+func RandomBits(n int, r *rand.Rand) Bits {
+	b := NewBits(n)
+
+	for i := range b.words {
+		b.words[i] = r.Uint64()
+	}
+
+	b.trim()
+
+	return b
+}
+
+// trim clears the bits of the last word beyond the length
+func (b Bits) trim() {
+	if k := b.n % 64; k != 0 {
+		b.words[len(b.words)-1] &= 1<<uint(k) - 1
+	}
+}
+
+// Len returns the number of bits of the receiver
+func (b Bits) Len() int {
+	return b.n
+}
+
+// Get reports whether the i-th bit is set
+func (b Bits) Get(i int) bool {
+	return b.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Set sets the i-th bit to value
+func (b Bits) Set(i int, value bool) {
+	if value {
+		b.words[i/64] |= 1 << uint(i%64)
+	} else {
+		b.words[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// Flip inverts the i-th bit
+func (b Bits) Flip(i int) {
+	b.words[i/64] ^= 1 << uint(i%64)
+}
+
+// Count returns the number of set bits
+func (b Bits) Count() int {
+	count := 0
+
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+
+	return count
+}
+
+// Clone the receiver bit string
+func (b Bits) Clone() Bits {
+	return Bits{append([]uint64(nil), b.words...), b.n}
+}
+
+// Values returns the bits of the receiver as zeros and ones
+func (b Bits) Values() []float64 {
+	values := make([]float64, b.n)
+
+	for i := range values {
+		if b.Get(i) {
+			values[i] = 1.
+		}
+	}
+
+	return values
+}
+
+// Hash returns the FNV-1a hash of the receiver
+func (b Bits) Hash() uint64 {
+	data, _ := b.MarshalBinary()
+
+	hash := fnv.New64a()
+	hash.Write(data)
+
+	return hash.Sum64()
+}
+
+// Equal reports whether the receiver and other have the same bits
+func (b Bits) Equal(other Bits) bool {
+	if b.n != other.n {
+		return false
+	}
+
+	for i := range b.words {
+		if b.words[i] != other.words[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the bits of the receiver as a string of zeros and ones, the first bit first
+func (b Bits) String() string {
+	var builder strings.Builder
+
+	for i := 0; i < b.n; i++ {
+		if b.Get(i) {
+			builder.WriteByte('1')
+		} else {
+			builder.WriteByte('0')
+		}
+	}
+
+	return builder.String()
+}
+
+// MarshalBinary encodes the receiver, it makes bit strings serialisable by encoding/gob
+func (b Bits) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8*(len(b.words)+1))
+
+	binary.LittleEndian.PutUint64(data, uint64(b.n))
+
+	for i, word := range b.words {
+		binary.LittleEndian.PutUint64(data[8*(i+1):], word)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a bit string encoded by MarshalBinary
+func (b *Bits) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 || len(data)%8 != 0 {
+		return errors.New("invalid bits: truncated data")
+	}
+
+	n := int(binary.LittleEndian.Uint64(data))
+	if n < 0 || (n+63)/64 != len(data)/8-1 {
+		return fmt.Errorf("invalid bits: length %d does not match %d bytes", n, len(data))
+	}
+
+	*b = NewBits(n)
+
+	for i := range b.words {
+		b.words[i] = binary.LittleEndian.Uint64(data[8*(i+1):])
+	}
+
+	return nil
+}
+
+// Uint decodes the width bits starting at offset as an unsigned integer, the first bit being the most significant
+func (b Bits) Uint(offset, width int) uint64 {
+	value := uint64(0)
+
+	for i := offset; i < offset+width; i++ {
+		value <<= 1
+
+		if b.Get(i) {
+			value |= 1
+		}
+	}
+
+	return value
+}
+
+// SetUint encodes value in the width bits starting at offset, the first bit being the most significant
+func (b Bits) SetUint(offset, width int, value uint64) {
+	for i := offset + width - 1; i >= offset; i-- {
+		b.Set(i, value&1 != 0)
+		value >>= 1
+	}
+}
+
+// Gray decodes the width bits starting at offset as a Gray-coded unsigned integer. Gray coding makes adjacent
+// integers differ by a single bit, so a bit flip moves the decoded value smoothly
+func (b Bits) Gray(offset, width int) uint64 {
+	return FromGray(b.Uint(offset, width))
+}
+
+// SetGray encodes value as a Gray code in the width bits starting at offset
+func (b Bits) SetGray(offset, width int, value uint64) {
+	b.SetUint(offset, width, ToGray(value))
+}
+
+// Float decodes the width bits starting at offset as a Gray-coded real in [min, max], with a resolution of
+// (max - min) / (2^width - 1)
+func (b Bits) Float(offset, width int, min, max float64) float64 {
+	if width == 0 {
+		return min
+	}
+
+	return min + (max-min)*float64(b.Gray(offset, width))/float64(uint64(math.MaxUint64)>>uint(64-width))
+}
+
+// ToGray returns the reflected binary Gray code of value
+func ToGray(value uint64) uint64 {
+	return value ^ value>>1
+}
+
+// FromGray returns the value whose reflected binary Gray code is gray
+func FromGray(gray uint64) uint64 {
+	for shift := uint(1); shift < 64; shift <<= 1 {
+		gray ^= gray >> shift
+	}
+
+	return gray
+}
+
+// BitFlip inverts every bit with the given Probability
+type BitFlip struct {
+	Probability float64
+}
+
+func (f BitFlip) Mutate(b Bits, r *rand.Rand) Bits {
+	if f.Probability <= 0 {
+		return b
+	}
+
+	if f.Probability >= 1 {
+		for i := 0; i < b.n; i++ {
+			b.Flip(i)
+		}
+
+		return b
+	}
+
+	// jump from a flipped bit to the next one drawing the geometric distribution of the gaps, so a low
+	// probability does not cost a random number per bit
+	logq := math.Log1p(-f.Probability)
+
+	// a gap is clamped to the length before the conversion, a tiny probability makes it overflow an int
+	gap := func() int {
+		return int(math.Min(math.Log(1-r.Float64())/logq, float64(b.n)))
+	}
+
+	for i := gap(); i < b.n; i += 1 + gap() {
+		b.Flip(i)
+	}
+
+	return b
+}
+
+// pair checks that parents are two bit strings of the same length
+func pair(parents []Bits) (Bits, Bits, error) {
+	if len(parents) != 2 {
+		return Bits{}, Bits{}, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	if parents[0].n != parents[1].n {
+		return Bits{}, Bits{}, fmt.Errorf("invalid parents length: %d != %d", parents[0].n, parents[1].n)
+	}
+
+	return parents[0], parents[1], nil
+}
+
+// splice returns the children taking the bits set in mask from mother and father respectively, and the others from the
+// other parent
+func splice(mother, father Bits, mask []uint64) []Bits {
+	children := []Bits{NewBits(mother.n), NewBits(mother.n)}
+
+	for i, m := range mask {
+		children[0].words[i] = mother.words[i]&m | father.words[i]&^m
+		children[1].words[i] = father.words[i]&m | mother.words[i]&^m
+	}
+
+	return children
+}
+
+// NPointCrossover cuts the parents at Points random positions and swaps every other segment
+// https://en.wikipedia.org/wiki/Crossover_(genetic_algorithm)#Two-point_and_k-point_crossover
+type NPointCrossover struct {
+	Points int
+}
+
+func (c NPointCrossover) Cross(parents []Bits, r *rand.Rand) ([]Bits, error) {
+	mother, father, err := pair(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Points < 1 || c.Points >= mother.n {
+		return nil, fmt.Errorf("invalid crossover points: %d (must be in [1, %d))", c.Points, mother.n)
+	}
+
+	// distinct cut points, each one is the last bit of a segment
+	points := r.Perm(mother.n - 1)[:c.Points]
+	sort.Ints(points)
+
+	mask := NewBits(mother.n)
+	from := 0
+
+	for k, point := range append(points, mother.n-1) {
+		if k%2 == 0 {
+			for i := from; i <= point; i++ {
+				mask.Set(i, true)
+			}
+		}
+
+		from = point + 1
+	}
+
+	return splice(mother, father, mask.words), nil
+}
+
+func (c NPointCrossover) Children() int {
+	return 2
+}
+
+// UniformBitCrossover takes every bit from either parent with even probability
+type UniformBitCrossover struct{}
+
+func (UniformBitCrossover) Cross(parents []Bits, r *rand.Rand) ([]Bits, error) {
+	mother, father, err := pair(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := make([]uint64, len(mother.words))
+
+	for i := range mask {
+		mask[i] = r.Uint64()
+	}
+
+	return splice(mother, father, mask), nil
+}
+
+func (UniformBitCrossover) Children() int {
+	return 2
+}
@@ -0,0 +1,202 @@
+package genetic
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"math/rand"
+	"testing"
+)
+
+func TestBits(t *testing.T) {
+	b := NewBits(130)
+
+	for _, i := range []int{0, 63, 64, 129} {
+		b.Set(i, true)
+	}
+
+	b.Flip(1)
+	b.Flip(0)
+
+	if got := b.Count(); got != 4 {
+		t.Errorf("b.Count() = %d, want 4", got)
+	}
+
+	if !b.Get(1) || b.Get(0) || !b.Get(129) {
+		t.Errorf("b = %v, want bits 1, 63, 64 and 129 set", b)
+	}
+
+	clone := b.Clone()
+	clone.Flip(2)
+
+	if b.Get(2) || b.Equal(clone) {
+		t.Errorf("b.Clone() shares the bits of b")
+	}
+
+	if r := RandomBits(70, rand.New(rand.NewSource(1))); r.words[1]>>6 != 0 {
+		t.Errorf("RandomBits(70) sets bits beyond its length")
+	}
+}
+
+func TestBits_Gob(t *testing.T) {
+	b := RandomBits(100, rand.New(rand.NewSource(1)))
+
+	var buffer bytes.Buffer
+
+	if err := gob.NewEncoder(&buffer).Encode(b); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Bits
+
+	if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(b) {
+		t.Errorf("decoded = %v, want %v", decoded, b)
+	}
+}
+
+func TestGray(t *testing.T) {
+	for value := uint64(0); value < 1024; value++ {
+		if got := FromGray(ToGray(value)); got != value {
+			t.Fatalf("FromGray(ToGray(%d)) = %d", value, got)
+		}
+
+		// adjacent values differ by a single bit
+		if d := ToGray(value) ^ ToGray(value+1); d&(d-1) != 0 {
+			t.Fatalf("ToGray(%d) and ToGray(%d) differ by more than a bit", value, value+1)
+		}
+	}
+
+	b := NewBits(16)
+	b.SetGray(4, 10, 700)
+
+	if got := b.Gray(4, 10); got != 700 {
+		t.Errorf("b.Gray() = %d, want 700", got)
+	}
+
+	b.SetGray(4, 10, 1023)
+
+	if got := b.Float(4, 10, -1, 1); got != 1 {
+		t.Errorf("b.Float() = %f, want 1", got)
+	}
+}
+
+func TestBits_Cross(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	crossovers := []Crossover[Bits]{NPointCrossover{Points: 1}, NPointCrossover{Points: 3}, UniformBitCrossover{}}
+
+	for _, crossover := range crossovers {
+		mother, father := RandomBits(150, r), RandomBits(150, r)
+
+		children, err := crossover.Cross([]Bits{mother, father}, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// every position keeps the bits of the parents, split between the children
+		for i := 0; i < mother.Len(); i++ {
+			a, b := children[0].Get(i), children[1].Get(i)
+
+			if !(a == mother.Get(i) && b == father.Get(i)) && !(a == father.Get(i) && b == mother.Get(i)) {
+				t.Fatalf("%T: bit %d of the children does not come from the parents", crossover, i)
+			}
+		}
+	}
+
+	if _, err := (NPointCrossover{Points: 10}).Cross([]Bits{NewBits(5), NewBits(5)}, r); err == nil {
+		t.Errorf("Cross() error = nil, want error for too many points")
+	}
+}
+
+func TestBitFlip_Mutate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	const n = 100000
+
+	if got := (BitFlip{Probability: .01}).Mutate(NewBits(n), r).Count(); got < n/200 || got > n/50 {
+		t.Errorf("BitFlip{.01} flipped %d bits out of %d", got, n)
+	}
+
+	if got := (BitFlip{Probability: 1}).Mutate(NewBits(n), r).Count(); got != n {
+		t.Errorf("BitFlip{1} flipped %d bits out of %d", got, n)
+	}
+
+	// the gaps between flips overflow an int if not clamped
+	for i := 0; i < 100; i++ {
+		if got := (BitFlip{Probability: 1e-19}).Mutate(NewBits(n), r).Count(); got > 1 {
+			t.Fatalf("BitFlip{1e-19} flipped %d bits out of %d", got, n)
+		}
+	}
+}
+
+func newBitsEngine(n int, evaluator func(Bits) float64) *Engine[Bits] {
+	return &Engine[Bits]{Configuration: Configuration[Bits]{
+		PopulationSize: 50,
+		MaxAge:         10,
+		Selection:      TournamentSelection{Size: 3},
+		Crossover:      UniformBitCrossover{},
+		Mutation:       BitFlip{Probability: 1. / float64(n)},
+		Elitism:        .1,
+		Iterations:     300,
+		Init: func(e *Engine[Bits]) {
+			for i := range e.Population {
+				e.Population[i].Genome = RandomBits(n, e.Rand())
+			}
+		},
+		Evaluator: evaluator,
+		Cache:     NewCache[Bits](1000),
+		Seed:      1,
+	}}
+}
+
+func TestEngine_RunOneMax(t *testing.T) {
+	engine := newBitsEngine(64, OneMax)
+	engine.Terminator = TargetFitness{Fitness: 64}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Best.Fitness != 64 {
+		t.Errorf("result.Best.Fitness = %f, want 64", result.Best.Fitness)
+	}
+}
+
+func TestEngine_RunKnapsack(t *testing.T) {
+	knapsack := Knapsack{
+		Weights:  []float64{23, 31, 29, 44, 53, 38, 63, 85, 89, 82},
+		Values:   []float64{92, 57, 49, 68, 60, 43, 67, 84, 87, 72},
+		Capacity: 165,
+	}
+
+	engine := newBitsEngine(len(knapsack.Weights), knapsack.Evaluate)
+	engine.Terminator = TargetFitness{Fitness: 309}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the optimum packs the first four items and the sixth one
+	if result.Best.Fitness != 309 {
+		t.Errorf("result.Best.Fitness = %f (%v), want 309", result.Best.Fitness, result.Best.Genome)
+	}
+}
+
+func BenchmarkEngine_RunOneMax(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		engine := newBitsEngine(1024, OneMax)
+		engine.Iterations = 10
+
+		if _, err := engine.Run(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
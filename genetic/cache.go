@@ -0,0 +1,247 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errAborted is the error of an evaluation that did not return, as a panicking one
+var errAborted = errors.New("evaluation aborted")
+
+// score is the outcome of an evaluation
+type score struct {
+	fitness    float64
+	objectives []float64
+}
+
+type entry[G any] struct {
+	key    uint64
+	genome G
+	value  score
+	err    error
+	// ready is closed once the evaluation has completed
+	ready chan struct{}
+}
+
+// hashable is implemented by the genomes that can be cached
+type hashable[G any] interface {
+	Hash() uint64
+	Equal(G) bool
+}
+
+// Cache is a bounded LRU cache of evaluations keyed by the hash of the genome, which must implement Hash() uint64
+// and Equal(G) bool as Chromosome does. It is safe for concurrent use: while a genome is being evaluated, lookups of
+// an identical one wait for its score instead of evaluating it again
+type Cache[G Genome[G]] struct {
+	size    int
+	mutex   sync.Mutex
+	entries map[uint64]*list.Element
+	order   *list.List
+	hits    int
+	misses  int
+}
+
+// NewCache returns a cache holding the scores of at most size genomes, size must be greater than zero
+func NewCache[G Genome[G]](size int) *Cache[G] {
+	if size < 1 {
+		panic(fmt.Sprintf("invalid argument: size = %d (must be greater than zero)", size))
+	}
+
+	return &Cache[G]{
+		size:    size,
+		entries: make(map[uint64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// supports reports whether G can be cached
+func (c *Cache[G]) supports() bool {
+	_, ok := any(*new(G)).(hashable[G])
+	return ok
+}
+
+func (c *Cache[G]) hash(genome G) uint64 {
+	return any(genome).(hashable[G]).Hash()
+}
+
+func (c *Cache[G]) equal(a, b G) bool {
+	return any(a).(hashable[G]).Equal(b)
+}
+
+// lookup returns the score of genome if cached, otherwise it evaluates and caches it. Failed evaluations are not
+// cached
+func (c *Cache[G]) lookup(genome G, evaluate func() (score, error)) (score, error) {
+	key := c.hash(genome)
+
+	e, cached := c.claim(key, genome)
+	if cached {
+		<-e.ready
+
+		if e.err == nil {
+			return e.value, nil
+		}
+
+		return evaluate()
+	}
+
+	// a failed evaluation, even a panicking one, is dropped and must not leave the lookups of genome waiting
+	e.err = errAborted
+
+	defer func() {
+		if e.err != nil {
+			c.forget(key, e)
+		}
+
+		close(e.ready)
+	}()
+
+	e.value, e.err = evaluate()
+
+	return e.value, e.err
+}
+
+// claim returns the entry of genome, and true, if cached. Otherwise it caches a new entry, to be made ready by the
+// caller, and returns it
+func (c *Cache[G]) claim(key uint64, genome G) (*entry[G], bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		if e := element.Value.(*entry[G]); c.equal(e.genome, genome) {
+			c.hits++
+			c.order.MoveToFront(element)
+
+			return e, true
+		}
+
+		// hash collision, the newest genome wins
+		c.remove(element)
+	}
+
+	c.misses++
+
+	e := &entry[G]{key: key, genome: genome.Clone(), ready: make(chan struct{})}
+	c.insert(e)
+
+	return e, false
+}
+
+// forget removes e, if still cached
+func (c *Cache[G]) forget(key uint64, e *entry[G]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok && element.Value == e {
+		c.remove(element)
+	}
+}
+
+// get returns the score of genome if cached, waiting for it if the genome is being evaluated
+func (c *Cache[G]) get(genome G) (score, bool) {
+	e := c.find(c.hash(genome), genome)
+	if e == nil {
+		return score{}, false
+	}
+
+	<-e.ready
+
+	return e.value, e.err == nil
+}
+
+// find returns the entry of genome, nil if not cached
+func (c *Cache[G]) find(key uint64, genome G) *entry[G] {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok || !c.equal(element.Value.(*entry[G]).genome, genome) {
+		c.misses++
+		return nil
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+
+	return element.Value.(*entry[G])
+}
+
+// put caches the score of genome
+func (c *Cache[G]) put(genome G, value score) {
+	key := c.hash(genome)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	e := &entry[G]{key: key, genome: genome.Clone(), value: value, ready: make(chan struct{})}
+	close(e.ready)
+
+	c.insert(e)
+}
+
+// insert caches e as the most recently used entry, evicting the least recently used ones beyond the size
+func (c *Cache[G]) insert(e *entry[G]) {
+	c.entries[e.key] = c.order.PushFront(e)
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache[G]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[G]).key)
+}
+
+// Hits returns the number of lookups that found the genome in the cache
+func (c *Cache[G]) Hits() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.hits
+}
+
+// Misses returns the number of lookups that required an evaluation
+func (c *Cache[G]) Misses() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.misses
+}
+
+// Len returns the number of genomes in the cache
+func (c *Cache[G]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
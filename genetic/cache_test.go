@@ -0,0 +1,142 @@
+package genetic
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_Lookup(t *testing.T) {
+	cache := NewCache[Chromosome](2)
+
+	chromosomes := make([]Chromosome, 3)
+
+	for i := range chromosomes {
+		chromosomes[i] = NewChromosome(2, 2)
+		chromosomes[i].Genes[0].Sequence[0] = float64(i)
+	}
+
+	evaluations := 0
+	lookup := func(c Chromosome) float64 {
+		value, err := cache.lookup(c, func() (score, error) {
+			evaluations++
+			return score{fitness: sum(c)}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return value.fitness
+	}
+
+	lookup(chromosomes[0])
+	lookup(chromosomes[1])
+
+	if fitness := lookup(chromosomes[0].Clone()); fitness != 0 {
+		t.Errorf("lookup(chromosomes[0]) = %f, want 0", fitness)
+	}
+
+	// chromosomes[1] is the least recently used one
+	lookup(chromosomes[2])
+	lookup(chromosomes[1])
+
+	if evaluations != 4 {
+		t.Errorf("evaluations = %d, want 4", evaluations)
+	}
+
+	if cache.Hits() != 1 || cache.Misses() != 4 {
+		t.Errorf("cache.Hits(), cache.Misses() = %d, %d, want 1, 4", cache.Hits(), cache.Misses())
+	}
+
+	if cache.Len() != 2 {
+		t.Errorf("cache.Len() = %d, want 2", cache.Len())
+	}
+}
+
+func TestCache_LookupConcurrent(t *testing.T) {
+	cache := NewCache[Chromosome](10)
+	chromosome := NewChromosome(4, 4)
+
+	var evaluations int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = cache.lookup(chromosome.Clone(), func() (score, error) {
+				atomic.AddInt64(&evaluations, 1)
+				time.Sleep(10 * time.Millisecond)
+				return score{fitness: 1}, nil
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	if evaluations != 1 {
+		t.Errorf("evaluations = %d, want 1", evaluations)
+	}
+}
+
+func TestEngine_RunCache(t *testing.T) {
+	engine := newTestEngine()
+	engine.Crossover = None[Chromosome]{}
+	engine.Mutation = Uniform{Probability: 0}
+	engine.Cache = NewCache[Chromosome](100)
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// clones of the initial population are never evaluated again
+	if engine.Evaluations() != engine.PopulationSize {
+		t.Errorf("engine.Evaluations() = %d, want %d", engine.Evaluations(), engine.PopulationSize)
+	}
+
+	if engine.Cache.Hits() == 0 {
+		t.Errorf("engine.Cache.Hits() = 0, want > 0")
+	}
+}
+
+func TestNewCache(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewCache(%d) did not panic", size)
+				}
+			}()
+
+			NewCache[Chromosome](size)
+		}()
+	}
+}
+
+func TestCache_LookupPanic(t *testing.T) {
+	cache := NewCache[Chromosome](10)
+	chromosome := NewChromosome(2, 2)
+
+	func() {
+		defer func() {
+			recover()
+		}()
+
+		cache.lookup(chromosome, func() (score, error) {
+			panic("failure")
+		})
+	}()
+
+	// the panicking evaluation is not cached, nor does it leave the cache locked
+	value, err := cache.lookup(chromosome, func() (score, error) {
+		return score{fitness: 1}, nil
+	})
+
+	if err != nil || value.fitness != 1 {
+		t.Errorf("lookup() = %v, %v, want 1", value.fitness, err)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by this package
+const SnapshotVersion = 2
+
+const snapshotMagic = "go-genetic snapshot"
+
+// Checkpoint tells the engine to save a snapshot to Path every Interval generations. A zero Interval disables it
+type Checkpoint struct {
+	Path     string
+	Interval int
+}
+
+// A Snapshot is the full state of an evolution at the end of a generation. Random streams are derived from Seed and
+// Generation, so resuming a snapshot continues exactly as the original run would have done. G must be encodable by
+// encoding/gob
+type Snapshot[G Genome[G]] struct {
+	Version     int
+	Seed        int64
+	Generation  int
+	Evaluations int
+	Elapsed     time.Duration
+	Best        Phenotype[G]
+	Improvement int
+	Population  []Phenotype[G]
+	History     []GenerationStats
+}
+
+// header precedes the snapshot in its serialised form
+type header struct {
+	Magic   string
+	Version int
+}
+
+// Snapshot returns the current state of the evolution. It can be called by the Observer or once the run has ended,
+// but not while a generation is running
+func (e *Engine[G]) Snapshot() Snapshot[G] {
+	population := make([]Phenotype[G], len(e.Population))
+
+	for i, phenotype := range e.Population {
+		population[i] = phenotype
+		population[i].Genome = phenotype.Genome.Clone()
+	}
+
+	best := e.best
+	best.Genome = best.Genome.Clone()
+
+	return Snapshot[G]{
+		Version:     SnapshotVersion,
+		Seed:        e.seed,
+		Generation:  e.generation,
+		Evaluations: e.Evaluations(),
+		Elapsed:     e.Elapsed(),
+		Best:        best,
+		Improvement: e.improvement,
+		Population:  population,
+		History:     append([]GenerationStats(nil), e.history...),
+	}
+}
+
+// Write serialises the snapshot to w
+func (s Snapshot[G]) Write(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+
+	if err := encoder.Encode(header{snapshotMagic, s.Version}); err != nil {
+		return err
+	}
+
+	return encoder.Encode(s)
+}
+
+// ReadSnapshot deserialises a snapshot written by Snapshot.Write
+func ReadSnapshot[G Genome[G]](r io.Reader) (Snapshot[G], error) {
+	decoder := gob.NewDecoder(r)
+
+	var h header
+
+	if err := decoder.Decode(&h); err != nil {
+		return Snapshot[G]{}, err
+	}
+
+	if h.Magic != snapshotMagic {
+		return Snapshot[G]{}, errors.New("invalid snapshot: not a snapshot")
+	}
+
+	if h.Version != SnapshotVersion {
+		return Snapshot[G]{}, fmt.Errorf("invalid snapshot version: %d (supported version is %d)", h.Version, SnapshotVersion)
+	}
+
+	var s Snapshot[G]
+
+	if err := decoder.Decode(&s); err != nil {
+		return Snapshot[G]{}, err
+	}
+
+	return s, nil
+}
+
+// Save writes the snapshot to the file at path. The data is synced to disk before the file is replaced atomically, so
+// a crash or a power loss while saving does not corrupt the previous snapshot
+func (s Snapshot[G]) Save(path string) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if err := s.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+
+	// persist the rename as well, directories cannot be synced on every platform so it is best effort
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	return nil
+}
+
+// LoadSnapshot reads the snapshot saved at path
+func LoadSnapshot[G Genome[G]](path string) (Snapshot[G], error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Snapshot[G]{}, err
+	}
+
+	defer file.Close()
+
+	return ReadSnapshot[G](file)
+}
+
+// Resume continues the evolution from snapshot as Run would do. The configuration should be the one of the run that
+// produced the snapshot, Init is not called
+func (e *Engine[G]) Resume(ctx context.Context, snapshot Snapshot[G]) (Result[G], error) {
+	if err := e.Validate(); err != nil {
+		return Result[G]{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return Result[G]{}, fmt.Errorf("invalid snapshot version: %d (supported version is %d)", snapshot.Version, SnapshotVersion)
+	}
+
+	if len(snapshot.Population) != e.PopulationSize {
+		return Result[G]{}, fmt.Errorf("invalid snapshot population size: %d != %d", len(snapshot.Population), e.PopulationSize)
+	}
+
+	e.reset(snapshot.Seed, snapshot.Population)
+	e.start = e.start.Add(-snapshot.Elapsed)
+	e.generation = snapshot.Generation
+	e.improvement = snapshot.Improvement
+	e.best = snapshot.Best
+	e.history = append([]GenerationStats(nil), snapshot.History...)
+
+	if len(e.history) > 0 {
+		e.statistics = e.history[len(e.history)-1]
+	}
+	atomic.StoreInt64(&e.evaluations, int64(snapshot.Evaluations))
+
+	if err := e.evolve(ctx, e.Iterations-e.generation); err != nil {
+		return e.result(), err
+	}
+
+	e.done(ctx)
+
+	return e.result(), nil
+}
@@ -0,0 +1,90 @@
+package genetic
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEngine_Resume(t *testing.T) {
+	var buffer bytes.Buffer
+
+	original := newTestEngine()
+	original.Seed = 42
+	original.Observer = func(i int, e *Engine[Chromosome]) {
+		if i == 19 {
+			if err := e.Snapshot().Write(&buffer); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	want, err := original.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := ReadSnapshot[Chromosome](&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Generation != 20 {
+		t.Errorf("snapshot.Generation = %d, want 20", snapshot.Generation)
+	}
+
+	resumed := newTestEngine()
+
+	got, err := resumed.Resume(context.Background(), snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Generations != want.Generations || got.Seed != want.Seed {
+		t.Errorf("Resume() = {Generations: %d, Seed: %d}, want {Generations: %d, Seed: %d}",
+			got.Generations, got.Seed, want.Generations, want.Seed)
+	}
+
+	if resumed.Evaluations() != original.Evaluations() {
+		t.Errorf("resumed.Evaluations() = %d, want %d", resumed.Evaluations(), original.Evaluations())
+	}
+
+	if !reflect.DeepEqual(resumed.Population, original.Population) {
+		t.Errorf("resumed.Population differs from the original one")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot")
+
+	engine := newTestEngine()
+	engine.Checkpoint = Checkpoint{Path: path, Interval: 15}
+
+	if _, err := engine.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := LoadSnapshot[Chromosome](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot.Generation != 45 {
+		t.Errorf("snapshot.Generation = %d, want 45", snapshot.Generation)
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		t.Errorf("snapshot.Version = %d, want %d", snapshot.Version, SnapshotVersion)
+	}
+}
@@ -24,23 +24,45 @@
 
 package genetic
 
-import "fmt"
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
 
 // A chromosome collects more genes
 type Chromosome struct {
 	Genes []Gene
 }
 
-// NewChromosome returns a new randomly chromosome
-func NewChromosome(length int, geneLength int) Chromosome {
+// MakeChromosome returns a new chromosome of length 'zero' genes of geneLength values, or an error if a length is
+// not valid
+func MakeChromosome(length int, geneLength int) (Chromosome, error) {
 	if length < 1 {
-		panic(fmt.Sprintf("invalid argument: length = %d (length must be greater than zero", length))
+		return Chromosome{}, fmt.Errorf("invalid chromosome length: %d (must be greater than zero)", length)
 	}
 
 	chromosome := Chromosome{make([]Gene, length)}
 
 	for i := range chromosome.Genes {
-		chromosome.Genes[i] = NewGene(geneLength)
+		gene, err := MakeGene(geneLength)
+		if err != nil {
+			return Chromosome{}, err
+		}
+
+		chromosome.Genes[i] = gene
+	}
+
+	return chromosome, nil
+}
+
+// NewChromosome is like MakeChromosome but panics if a length is not valid, it is meant for lengths known to be
+// valid, as constants. Lengths coming from input or configuration should go through MakeChromosome
+func NewChromosome(length int, geneLength int) Chromosome {
+	chromosome, err := MakeChromosome(length, geneLength)
+	if err != nil {
+		panic(err)
 	}
 
 	return chromosome
@@ -57,31 +79,52 @@ func (c Chromosome) Clone() Chromosome {
 	return chromosome
 }
 
-// Execute mutation on receiver using mutator
-func (c *Chromosome) Mutate(mutator Mutator) {
-	for i := range c.Genes {
-		mutator.Mutate(&c.Genes[i])
+// Hash returns the FNV-1a hash of the receiver genes
+func (c Chromosome) Hash() uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, gene := range c.Genes {
+		binary.LittleEndian.PutUint64(buffer, uint64(len(gene.Sequence)))
+		hash.Write(buffer)
+
+		for _, value := range gene.Sequence {
+			binary.LittleEndian.PutUint64(buffer, math.Float64bits(value))
+			hash.Write(buffer)
+		}
 	}
-}
 
-// Phenotype encapsulates a chromosome with its relative fitness score and age
-type Phenotype struct {
-	Chromosome
-	Fitness float64
-	Age     int
+	return hash.Sum64()
 }
 
-// sort.Interface implementation for Phenotype slice
-type decreasing []Phenotype
+// Equal reports whether the receiver and other have the same genes
+func (c Chromosome) Equal(other Chromosome) bool {
+	if len(c.Genes) != len(other.Genes) {
+		return false
+	}
 
-func (p decreasing) Len() int {
-	return len(p)
-}
+	for i := range c.Genes {
+		if len(c.Genes[i].Sequence) != len(other.Genes[i].Sequence) {
+			return false
+		}
+
+		for j := range c.Genes[i].Sequence {
+			if c.Genes[i].Sequence[j] != other.Genes[i].Sequence[j] {
+				return false
+			}
+		}
+	}
 
-func (p decreasing) Swap(i, j int) {
-	p[i], p[j] = p[j], p[i]
+	return true
 }
 
-func (p decreasing) Less(i, j int) bool {
-	return p[i].Fitness > p[j].Fitness
+// Values returns the gene values of the receiver, one after the other
+func (c Chromosome) Values() []float64 {
+	values := make([]float64, 0, len(c.Genes))
+
+	for _, gene := range c.Genes {
+		values = append(values, gene.Sequence...)
+	}
+
+	return values
 }
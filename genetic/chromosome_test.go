@@ -23,3 +23,28 @@ func TestChromosome_Clone(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeChromosome(t *testing.T) {
+	if c, err := MakeChromosome(3, 2); err != nil || len(c.Genes) != 3 || len(c.Genes[2].Sequence) != 2 {
+		t.Errorf("MakeChromosome(3, 2) = %v, %v", c, err)
+	}
+
+	for _, lengths := range [][2]int{{0, 2}, {-1, 2}, {3, -1}} {
+		if _, err := MakeChromosome(lengths[0], lengths[1]); err == nil {
+			t.Errorf("MakeChromosome(%d, %d) error = nil, want error", lengths[0], lengths[1])
+		}
+	}
+}
+
+func TestCrossover_CrossMisaligned(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, crossover := range []Crossover[Chromosome]{SinglePointCrossover{}, UniformCrossover{}} {
+		for _, parents := range [][]Chromosome{{NewChromosome(3, 1), NewChromosome(2, 1)}, {{}, {}}} {
+			if _, err := crossover.Cross(parents, r); err == nil {
+				t.Errorf("%T: Cross() error = nil, want error for parents of %d and %d genes", crossover,
+					len(parents[0].Genes), len(parents[1].Genes))
+			}
+		}
+	}
+}
@@ -29,15 +29,19 @@ import (
 	"math/rand"
 )
 
-type Crossover interface {
-	Cross([]Chromosome) ([]Chromosome, error)
+// A Crossover recombines parents into new children using r as source of randomness. Children returns the number of
+// parents it needs, which is also the number of children it produces. The children must not share any state with
+// the parents, since they are mutated afterwards
+type Crossover[G any] interface {
+	Cross(parents []G, r *rand.Rand) ([]G, error)
 	Children() int
 }
 
-type None struct{}
+// None returns a copy of its only parent, so the offspring only differs from the population by mutation
+type None[G Genome[G]] struct{}
 
-func (n None) Cross(parents []Chromosome) ([]Chromosome, error) {
-	var children []Chromosome
+func (n None[G]) Cross(parents []G, r *rand.Rand) ([]G, error) {
+	var children []G
 
 	for _, p := range parents {
 		children = append(children, p.Clone())
@@ -46,7 +50,7 @@ func (n None) Cross(parents []Chromosome) ([]Chromosome, error) {
 	return children, nil
 }
 
-func (n None) Children() int {
+func (n None[G]) Children() int {
 	return 1
 }
 
@@ -68,20 +72,36 @@ func (t Text) String() string {
 
 type SinglePointCrossover struct{}
 
+// aligned checks that parents are two non-empty chromosomes of the same length
+func aligned(parents []Chromosome) (Chromosome, Chromosome, error) {
+	mother, father := parents[0], parents[1]
+
+	if len(mother.Genes) == 0 || len(mother.Genes) != len(father.Genes) {
+		return Chromosome{}, Chromosome{}, fmt.Errorf("invalid parents length: %d, %d (must be equal and greater than zero)",
+			len(mother.Genes), len(father.Genes))
+	}
+
+	return mother, father, nil
+}
+
 // Return two child applying single point crossover on parents
 // https://en.wikipedia.org/wiki/Crossover_(genetic_algorithm)#Single-point_crossover
-func (s SinglePointCrossover) Cross(parents []Chromosome) ([]Chromosome, error) {
+func (s SinglePointCrossover) Cross(parents []Chromosome, r *rand.Rand) ([]Chromosome, error) {
 	if len(parents) != 2 {
 		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
 	}
 
-	mother, father := parents[0], parents[1]
-	pivot := rand.Intn(len(mother.Genes))
+	mother, father, err := aligned(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	pivot := r.Intn(len(mother.Genes))
 
 	children := make([]Chromosome, 2)
 
-	children[0] = NewChromosome(len(mother.Genes), len(mother.Genes[0].Sequence))
-	children[1] = NewChromosome(len(mother.Genes), len(mother.Genes[0].Sequence))
+	children[0] = Chromosome{make([]Gene, len(mother.Genes))}
+	children[1] = Chromosome{make([]Gene, len(mother.Genes))}
 
 	for i := 0; i < pivot; i++ {
 		children[0].Genes[i] = mother.Genes[i].Clone()
@@ -102,20 +122,23 @@ func (s SinglePointCrossover) Children() int {
 
 type UniformCrossover struct{}
 
-func (u UniformCrossover) Cross(parents []Chromosome) ([]Chromosome, error) {
+func (u UniformCrossover) Cross(parents []Chromosome, r *rand.Rand) ([]Chromosome, error) {
 	if len(parents) != 2 {
 		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
 	}
 
-	mother, father := parents[0], parents[1]
+	mother, father, err := aligned(parents)
+	if err != nil {
+		return nil, err
+	}
 
 	children := make([]Chromosome, 2)
 
-	children[0] = NewChromosome(len(mother.Genes), len(mother.Genes[0].Sequence))
-	children[1] = NewChromosome(len(mother.Genes), len(mother.Genes[0].Sequence))
+	children[0] = Chromosome{make([]Gene, len(mother.Genes))}
+	children[1] = Chromosome{make([]Gene, len(mother.Genes))}
 
 	for i := 0; i < len(mother.Genes); i++ {
-		if rand.Float64() < .5 {
+		if r.Float64() < .5 {
 			children[0].Genes[i] = mother.Genes[i].Clone()
 			children[1].Genes[i] = father.Genes[i].Clone()
 		} else {
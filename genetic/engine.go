@@ -1,25 +1,132 @@
 package genetic
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type Configuration struct {
-	GeneLength       int
-	ChromosomeLength int
-	PopulationSize   int
-	MaxAge           int
+// Configuration of an evolution of genomes of type G
+type Configuration[G Genome[G]] struct {
+	PopulationSize int
+	MaxAge         int
+	// Direction of the optimisation, by default greater fitness is better
+	Direction Direction
 	Selection
-	Crossover
-	Mutation   Mutator
+	Crossover[G]
+	Mutation   Mutator[G]
 	Elitism    float64
 	Iterations int
-	Init       func(*Engine)
-	Evaluator  func(Chromosome) float64
-	Observer   func(int, *Engine)
+	// Init sets the genome of every individual of the population, which is allocated beforehand
+	Init      func(*Engine[G])
+	Evaluator func(G) float64
+	// ContextEvaluator, if set, is preferred over Evaluator. The context is the one passed to Engine.Run, so a
+	// long-running evaluation can abort returning ctx.Err()
+	ContextEvaluator func(context.Context, G) (float64, error)
+	// BatchEvaluator, if set, is preferred over Evaluator and ContextEvaluator: it receives all the genomes of a
+	// generation that need an evaluation at once
+	BatchEvaluator BatchEvaluator[G]
+	// MultiEvaluator, if set, switches the engine to multi-objective optimisation (NSGA-II). It returns the vector of
+	// objectives of a genome, all of them optimised according to Direction. Elitism and MaxAge are ignored
+	MultiEvaluator func(context.Context, G) ([]float64, error)
+	Observer       func(int, *Engine[G])
+	// Terminator, if set, is checked before every generation and ends the evolution as soon as it triggers
+	Terminator Terminator
+	// Checkpoint, if set, periodically saves a snapshot of the evolution
+	Checkpoint Checkpoint
+	// Cache, if set, stores the scores of the evaluated genomes so that identical ones are not evaluated again.
+	// It can be shared among engines using the same evaluator, G must implement Hash and Equal (see Cache)
+	Cache *Cache[G]
+	// Replacement, if set, switches the engine to steady-state evolution: instead of building a whole new offspring,
+	// every step breeds a few children from the current population and Replacement decides which individuals they
+	// replace. Elitism and MaxAge are ignored
+	Replacement Replacement
+	// InFlight, if set together with Replacement, switches the engine to asynchronous steady-state evolution: InFlight
+	// evaluations are kept running and each child is inserted as soon as its evaluation completes. Suited to slow
+	// evaluators whose latency varies a lot, but runs are not reproducible and an aborted generation is not rolled back
+	InFlight int
+	// Replacements is the number of steps making up a generation in steady-state evolution, the Observer is called,
+	// and the Terminator checked, once per generation. If zero, it is PopulationSize
+	Replacements int
+	// Workers is the size of the pool of goroutines breeding and evaluating the offspring, if zero it is GOMAXPROCS
+	Workers int
+	// HistorySize is the maximum number of generation statistics kept in the history, the oldest ones are dropped
+	// first. If zero, the statistics of every generation are kept
+	HistorySize int
+	// Seed of the random generator driving the evolution, runs with the same seed and configuration are identical.
+	// If zero, a seed is drawn from the current time
+	Seed int64
+}
+
+// Validate returns an error describing the first invalid field of the configuration, if any
+func (c Configuration[G]) Validate() error {
+	switch {
+	case c.PopulationSize < 1:
+		return fmt.Errorf("invalid population size: %d (must be greater than zero)", c.PopulationSize)
+	case c.MaxAge < 0:
+		return fmt.Errorf("invalid max age: %d (must not be negative)", c.MaxAge)
+	case c.Elitism < 0 || c.Elitism > 1:
+		return fmt.Errorf("invalid elitism: %v (must be in [0, 1])", c.Elitism)
+	case c.Replacements < 0:
+		return fmt.Errorf("invalid replacements: %d (must not be negative)", c.Replacements)
+	case c.Replacement != nil && c.MultiEvaluator != nil:
+		return errors.New("invalid replacement: steady-state evolution does not support multi-objective optimisation")
+	case c.InFlight < 0:
+		return fmt.Errorf("invalid in-flight evaluations: %d (must not be negative)", c.InFlight)
+	case c.InFlight > 0 && c.Replacement == nil:
+		return errors.New("invalid in-flight evaluations: asynchronous evolution requires a replacement")
+	case c.Workers < 0:
+		return fmt.Errorf("invalid workers: %d (must not be negative)", c.Workers)
+	case c.HistorySize < 0:
+		return fmt.Errorf("invalid history size: %d (must not be negative)", c.HistorySize)
+	case c.Iterations < 0:
+		return fmt.Errorf("invalid iterations: %d (must not be negative)", c.Iterations)
+	case c.Selection == nil:
+		return errors.New("missing selection")
+	case c.Crossover == nil:
+		return errors.New("missing crossover")
+	case c.Mutation == nil:
+		return errors.New("missing mutation")
+	case c.Init == nil:
+		return errors.New("missing init")
+	case c.Evaluator == nil && c.ContextEvaluator == nil && c.BatchEvaluator == nil && c.MultiEvaluator == nil:
+		return errors.New("missing evaluator")
+	case c.BatchEvaluator != nil && c.MultiEvaluator != nil:
+		return errors.New("invalid evaluator: batch evaluation does not support multi-objective optimisation")
+	}
+
+	if n := c.Crossover.Children(); n < 1 || n > c.PopulationSize {
+		return fmt.Errorf("invalid crossover children: %d (must be in [1, %d])", n, c.PopulationSize)
+	}
+
+	if c.Cache != nil && !c.Cache.supports() {
+		return fmt.Errorf("invalid cache: %T does not implement Hash and Equal", *new(G))
+	}
+
+	if t, ok := c.Selection.(TournamentSelection); ok && (t.Size < 1 || t.Size > c.PopulationSize) {
+		return fmt.Errorf("invalid tournament size: %d (must be in [1, %d])", t.Size, c.PopulationSize)
+	}
+
+	if _, ok := any(*new(G)).(Vector); !ok && diversity(c.Terminator) {
+		return fmt.Errorf("invalid terminator: minimum diversity requires %T to implement Vector", *new(G))
+	}
+
+	if c.Checkpoint.Interval < 0 || (c.Checkpoint.Interval > 0 && c.Checkpoint.Path == "") {
+		return fmt.Errorf("invalid checkpoint: %+v (interval must not be negative and path must be set)", c.Checkpoint)
+	}
+
+	if t, ok := c.Selection.(CrowdedTournamentSelection); ok && (t.Size < 1 || t.Size > c.PopulationSize) {
+		return fmt.Errorf("invalid tournament size: %d (must be in [1, %d])", t.Size, c.PopulationSize)
+	}
+
+	return nil
 }
 
 type AtomicBool struct {
@@ -27,128 +134,694 @@ type AtomicBool struct {
 	mutex sync.Mutex
 }
 
-type Engine struct {
-	Configuration
-	Population []Phenotype
+// Termination describes why an evolution ended
+type Termination int
+
+const (
+	// Completed means that all the configured iterations have been executed
+	Completed Termination = iota
+	// Stopped means that Engine.Stop has been called
+	Stopped
+	// Canceled means that the context passed to Engine.Run has been canceled
+	Canceled
+	// DeadlineExceeded means that the deadline of the context passed to Engine.Run has expired
+	DeadlineExceeded
+	// Terminated means that the configured Terminator has triggered
+	Terminated
+)
+
+func (t Termination) String() string {
+	switch t {
+	case Completed:
+		return "completed"
+	case Stopped:
+		return "stopped"
+	case Canceled:
+		return "canceled"
+	case DeadlineExceeded:
+		return "deadline exceeded"
+	case Terminated:
+		return "terminated"
+	default:
+		return "unknown"
+	}
+}
+
+// Result of an evolution
+type Result[G any] struct {
+	Best        Phenotype[G]
+	Generations int
+	Elapsed     time.Duration
+	Reason      Termination
+	// Criterion is the one that triggered when Reason is Terminated
+	Criterion Terminator
+	Seed      int64
+	// Front is the Pareto front of the last population in multi-objective optimisation
+	Front []Phenotype[G]
+	// History collects the statistics of every generation, see Configuration.HistorySize
+	History []GenerationStats
+}
+
+// Engine evolves a population of genomes of type G
+type Engine[G Genome[G]] struct {
+	Configuration[G]
+	Population []Phenotype[G]
 	mutex      sync.Mutex
 	running    bool
+	seed       int64
+	random     *rand.Rand
+	start      time.Time
+	generation int
+	// evaluations is accessed atomically
+	evaluations int64
+	best        Phenotype[G]
+	improvement int
+	finished    bool
+	reason      Termination
+	criterion   Terminator
+	statistics  GenerationStats
+	history     []GenerationStats
+	pipeline    *pipeline[G]
 }
 
-func (e *Engine) Start() (Phenotype, time.Duration) {
-	rand.Seed(time.Now().UnixNano())
+// Start runs the evolution until all the iterations have been executed or Stop is called. It panics if the
+// configuration is not valid or an operator fails, use Run to get an error instead
+func (e *Engine[G]) Start() (Phenotype[G], time.Duration) {
+	result, err := e.Run(context.Background())
+	if err != nil {
+		panic(err)
+	}
 
-	e.Population = make([]Phenotype, e.PopulationSize)
+	return result.Best, result.Elapsed
+}
 
-	for i := range e.Population {
-		chromosome := NewChromosome(e.ChromosomeLength, e.GeneLength)
-		e.Population[i] = Phenotype{chromosome, e.Evaluator(chromosome), 0}
+// Run runs the evolution until all the iterations have been executed, Stop is called or ctx is done. A canceled
+// context aborts the running generation, the population is left as it was at the end of the previous one.
+// An error is returned if the configuration is not valid or an operator fails, a done context is not an error
+func (e *Engine[G]) Run(ctx context.Context) (Result[G], error) {
+	if err := e.Validate(); err != nil {
+		return Result[G]{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := e.initialize(ctx); err != nil {
+		return e.result(), err
+	}
+
+	if err := e.evolve(ctx, e.Iterations); err != nil {
+		return e.result(), err
 	}
 
-	e.Configuration.Init(e)
+	e.done(ctx)
 
+	return e.result(), nil
+}
+
+// reset sets the state of the engine as if seed and population were the initial ones
+func (e *Engine[G]) reset(seed int64, population []Phenotype[G]) {
+	e.start = time.Now()
+	e.generation = 0
+	e.improvement = 0
+	e.finished = false
+	e.reason = Completed
+	e.criterion = nil
+	atomic.StoreInt64(&e.evaluations, 0)
+
+	e.seed = seed
+	if e.seed == 0 {
+		e.seed = e.start.UnixNano()
+	}
+
+	e.mutex.Lock()
 	e.running = true
+	e.mutex.Unlock()
 
-	start := time.Now()
+	e.Population = population
+	e.statistics = GenerationStats{}
+	e.history = nil
+}
 
-	for i := 0; i < e.Iterations; i++ {
-		e.mutex.Lock()
+// initialize creates and evaluates the initial population
+func (e *Engine[G]) initialize(ctx context.Context) error {
+	e.reset(e.Seed, nil)
 
-		if !e.running {
-			e.mutex.Unlock()
-			break
-		}
+	e.Population = make([]Phenotype[G], e.PopulationSize)
+	e.random = derive(e.seed, -1)
 
-		e.mutex.Unlock()
+	// Init validates what the configuration cannot, as the lengths of the chromosomes passed to NewChromosome
+	err := safely(func() error {
+		e.Configuration.Init(e)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("initialization: %w", err)
+	}
 
-		sort.Sort(decreasing(e.Population))
+	genomes := make([]G, len(e.Population))
 
-		// Elitism
-		survivors := int(bound(0., e.Elitism*float64(e.PopulationSize), float64(e.PopulationSize)))
+	for i := range e.Population {
+		genomes[i] = e.Population[i].Genome
+	}
 
-		var wg sync.WaitGroup
-		mutex := &sync.Mutex{}
+	phenotypes, err := e.evaluateAll(ctx, genomes)
+	if err != nil {
+		if ctx.Err() == nil {
+			return fmt.Errorf("initialization: %w", err)
+		}
 
-		offspring := make([]Phenotype, 0, e.PopulationSize)
+		e.finish(reason(ctx), nil)
+	} else {
+		e.Population = phenotypes
+	}
 
-		for i := range e.Population {
-			if len(offspring) >= survivors {
-				break
+	e.rank()
+	e.best = e.Best()
+	e.record()
+
+	return nil
+}
+
+// evolve runs at most n generations, stopping as soon as the evolution is done
+func (e *Engine[G]) evolve(ctx context.Context, n int) error {
+	for i := 0; i < n && !e.done(ctx); i++ {
+		if err := e.step(ctx); err != nil {
+			if ctx.Err() == nil {
+				e.drain()
+				return fmt.Errorf("generation %d: %w", e.generation, err)
 			}
 
+			e.finish(reason(ctx), nil)
+		}
+	}
+
+	return nil
+}
+
+// step runs a single generation
+func (e *Engine[G]) step(ctx context.Context) error {
+	e.random = derive(e.seed, e.generation)
+
+	offspring, err := e.offspring(ctx, e.generation)
+	if err != nil {
+		return err
+	}
+
+	e.Population = offspring
+	e.generation++
+
+	if best := e.Best(); e.Direction.Better(best.Fitness, e.best.Fitness) {
+		e.best = best
+		e.improvement = e.generation
+	}
+
+	e.record()
+
+	if e.Observer != nil {
+		e.Observer(e.generation-1, e)
+	}
+
+	if e.Checkpoint.Interval > 0 && e.generation%e.Checkpoint.Interval == 0 {
+		if err := e.Snapshot().Save(e.Checkpoint.Path); err != nil {
+			return fmt.Errorf("checkpoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// done reports whether the evolution has to end, recording the reason
+func (e *Engine[G]) done(ctx context.Context) bool {
+	if e.finished {
+		return true
+	}
+
+	if e.generation >= e.Iterations {
+		e.finish(Completed, nil)
+		return true
+	}
+
+	if ctx.Err() != nil {
+		e.finish(reason(ctx), nil)
+		return true
+	}
+
+	e.mutex.Lock()
+	running := e.running
+	e.mutex.Unlock()
+
+	if !running {
+		e.finish(Stopped, nil)
+		return true
+	}
+
+	if e.Terminator != nil {
+		if criterion := e.Terminator.Terminate(e); criterion != nil {
+			e.finish(Terminated, criterion)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Engine[G]) finish(reason Termination, criterion Terminator) {
+	e.drain()
+
+	e.finished = true
+	e.reason = reason
+	e.criterion = criterion
+}
+
+// result summarises the current state of the evolution
+func (e *Engine[G]) result() Result[G] {
+	result := Result[G]{
+		Best:        e.Best(),
+		Generations: e.generation,
+		Elapsed:     e.Elapsed(),
+		Reason:      e.reason,
+		Criterion:   e.criterion,
+		Seed:        e.seed,
+		History:     e.History(),
+	}
+
+	if e.MultiEvaluator != nil {
+		result.Front = e.ParetoFront()
+	}
+
+	return result
+}
+
+// reason maps the error of a done context to its termination reason
+func reason(ctx context.Context) Termination {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return DeadlineExceeded
+	}
+
+	return Canceled
+}
+
+// evaluate scores genome, looking it up in the cache first if any
+func (e *Engine[G]) evaluate(ctx context.Context, genome G) (Phenotype[G], error) {
+	var value score
+	var err error
+
+	if e.Cache != nil {
+		value, err = e.Cache.lookup(genome, func() (score, error) {
+			return e.score(ctx, genome)
+		})
+	} else {
+		value, err = e.score(ctx, genome)
+	}
+
+	return Phenotype[G]{Genome: genome, Score: Score{Fitness: value.fitness, Objectives: value.objectives}}, err
+}
+
+// score calls the evaluator on genome, a panicking evaluator is reported as an error
+func (e *Engine[G]) score(ctx context.Context, genome G) (value score, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	atomic.AddInt64(&e.evaluations, 1)
+
+	switch {
+	case e.MultiEvaluator != nil:
+		value.objectives, err = e.MultiEvaluator(ctx, genome)
+		if err == nil && len(value.objectives) > 0 {
+			value.fitness = value.objectives[0]
+		}
+	case e.ContextEvaluator != nil:
+		value.fitness, err = e.ContextEvaluator(ctx, genome)
+	default:
+		value.fitness = e.Evaluator(genome)
+	}
+
+	return value, err
+}
+
+// Rand returns the random generator of the current phase of the evolution, it can be used by Init and Observer to
+// keep a run reproducible. It must not be used concurrently
+func (e *Engine[G]) Rand() *rand.Rand {
+	return e.random
+}
+
+// offspring returns the next generation, or the first error occurred while generating it
+func (e *Engine[G]) offspring(ctx context.Context, generation int) ([]Phenotype[G], error) {
+	if e.Replacement != nil && e.InFlight > 0 {
+		return e.async(ctx)
+	}
+
+	if e.Replacement != nil {
+		return e.steady(ctx, generation)
+	}
+
+	if e.MultiEvaluator != nil {
+		children, err := e.breed(ctx, generation, e.PopulationSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range e.Population {
 			e.Population[i].Age++
+		}
 
-			if !(e.Population[i].Age > e.MaxAge) {
-				offspring = append(offspring, e.Population[i])
-			}
+		return e.survive(append(children, e.Population...), e.PopulationSize), nil
+	}
+
+	e.rank()
+
+	// Elitism
+	survivors := int(bound(0., e.Elitism*float64(e.PopulationSize), float64(e.PopulationSize)))
+
+	offspring := make([]Phenotype[G], 0, e.PopulationSize)
+
+	for i := range e.Population {
+		if len(offspring) >= survivors {
+			break
 		}
 
-		size := len(offspring)
+		e.Population[i].Age++
 
-		for size < e.PopulationSize {
-			wg.Add(1)
-			size += e.Crossover.Children()
+		if !(e.Population[i].Age > e.MaxAge) {
+			offspring = append(offspring, e.Population[i])
+		}
+	}
 
-			go func() {
-				defer wg.Done()
+	children, err := e.breed(ctx, generation, e.PopulationSize-len(offspring))
+	if err != nil {
+		return nil, err
+	}
 
-				parents, err := e.Selection.Select(e.Population, e.Crossover.Children())
-				if err != nil {
-					panic(err)
-				}
+	return append(offspring, children...)[:e.PopulationSize], nil
+}
 
-				children, err := e.Crossover.Cross(parents)
-				if err != nil {
-					panic(err)
-				}
+// rank sorts the population from the best to the worst individual
+func (e *Engine[G]) rank() {
+	if e.MultiEvaluator != nil {
+		e.Population = e.survive(e.Population, len(e.Population))
+		return
+	}
 
-				for i := range children {
-					children[i].Mutate(e.Mutation)
+	sort.Sort(ranking[G]{e.Population, e.Direction})
+}
 
-					phenotype := Phenotype{children[i], e.Evaluator(children[i]), 0}
+// breed returns at least n evaluated children of the current population, or the first error occurred while
+// generating them
+func (e *Engine[G]) breed(ctx context.Context, generation int, n int) ([]Phenotype[G], error) {
+	// each task produces its children in its own slot using its own random stream, so the offspring does not
+	// depend on the scheduling of the workers
+	children := e.Crossover.Children()
+	slots := make([][]G, (n+children-1)/children)
+	population := scores(e.Population)
+	selection := e.prepare(population)
+
+	err := parallel(e.workers(), len(slots), func(i int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-					mutex.Lock()
-					offspring = append(offspring, phenotype)
-					mutex.Unlock()
-				}
-			}()
+		_, genomes, err := e.reproduce(e.Population, selection, population, derive(e.seed, generation, i))
+		if err != nil {
+			return err
 		}
 
-		wg.Wait()
-		e.Population = offspring[:e.PopulationSize]
+		slots[i] = genomes
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	genomes := make([]G, 0, len(slots)*children)
+
+	for _, slot := range slots {
+		genomes = append(genomes, slot...)
+	}
+
+	return e.evaluateAll(ctx, genomes)
+}
+
+// prepare returns the selection to use on the population whose scores are scores, see Preparer
+func (e *Engine[G]) prepare(scores []Score) Selection {
+	if p, ok := e.Selection.(Preparer); ok {
+		return p.Prepare(scores, e.Direction)
+	}
+
+	return e.Selection
+}
+
+// reproduce selects, with s, parents from population, whose scores are scores, and returns their indexes together
+// with their mutated children
+func (e *Engine[G]) reproduce(population []Phenotype[G], s Selection, scores []Score, r *rand.Rand) ([]int, []G, error) {
+	parents, err := s.Select(scores, e.Crossover.Children(), r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("selection: %w", err)
+	}
 
-		if e.Observer != nil {
-			e.Observer(i, e)
+	genomes := make([]G, len(parents))
+
+	for i, parent := range parents {
+		genomes[i] = population[parent].Genome
+	}
+
+	children, err := e.Crossover.Cross(genomes, r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("crossover: %w", err)
+	}
+
+	if len(children) == 0 {
+		return nil, nil, errors.New("crossover: no children")
+	}
+
+	for i := range children {
+		children[i] = e.Mutation.Mutate(children[i], r)
+	}
+
+	return parents, children, nil
+}
+
+// evaluateAll scores genomes with the batch evaluator if any, on the worker pool otherwise
+func (e *Engine[G]) evaluateAll(ctx context.Context, genomes []G) ([]Phenotype[G], error) {
+	if e.BatchEvaluator != nil {
+		return e.evaluateBatch(ctx, genomes)
+	}
+
+	phenotypes := make([]Phenotype[G], len(genomes))
+
+	err := parallel(e.workers(), len(genomes), func(i int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		phenotype, err := e.evaluate(ctx, genomes[i])
+		if err != nil {
+			return fmt.Errorf("evaluation: %w", err)
+		}
+
+		phenotypes[i] = phenotype
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if e.MultiEvaluator != nil {
+		if err := e.objectives(phenotypes); err != nil {
+			return nil, err
 		}
 	}
 
-	return e.Best(), time.Since(start)
+	return phenotypes, nil
 }
 
-func (e *Engine) Stop() {
+// objectives checks that phenotypes have as many objectives as the evaluated population, or as each other while the
+// population is being initialised, since dominance and crowding compare the vectors element by element
+func (e *Engine[G]) objectives(phenotypes []Phenotype[G]) error {
+	if len(phenotypes) == 0 {
+		return nil
+	}
+
+	want := len(phenotypes[0].Objectives)
+	if len(e.Population) > 0 && len(e.Population[0].Objectives) > 0 {
+		want = len(e.Population[0].Objectives)
+	}
+
+	if want == 0 {
+		return errors.New("evaluation: missing objectives")
+	}
+
+	for _, phenotype := range phenotypes {
+		if len(phenotype.Objectives) != want {
+			return fmt.Errorf("evaluation: invalid objectives size: %d != %d", len(phenotype.Objectives), want)
+		}
+	}
+
+	return nil
+}
+
+// workers returns the size of the worker pool
+func (c Configuration[G]) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// safely calls f turning a panic into an error, a panicking operator must not take down the whole process
+func safely(f func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	return f()
+}
+
+// parallel calls f for every task in [0, n) on a pool of workers goroutines, it returns the first error and stops
+// handing out tasks as soon as one fails. A panicking task is reported as an error
+func parallel(workers int, n int, f func(int) error) error {
+	if workers > n {
+		workers = n
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var failure error
+	var next, failed int64
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for atomic.LoadInt64(&failed) == 0 {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n {
+					return
+				}
+
+				if err := safely(func() error { return f(i) }); err != nil {
+					once.Do(func() { failure = err })
+					atomic.StoreInt64(&failed, 1)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return failure
+}
+
+func (e *Engine[G]) Stop() {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
 	e.running = false
 }
 
-func (e *Engine) Best() Phenotype {
+// Generation returns the number of generations completed so far
+func (e *Engine[G]) Generation() int {
+	return e.generation
+}
+
+// Evaluations returns the number of evaluations performed so far
+func (e *Engine[G]) Evaluations() int {
+	return int(atomic.LoadInt64(&e.evaluations))
+}
+
+// Elapsed returns the time elapsed since the start of the evolution
+func (e *Engine[G]) Elapsed() time.Duration {
+	return time.Since(e.start)
+}
+
+// Stagnation returns the number of generations since the best fitness found so far last improved
+func (e *Engine[G]) Stagnation() int {
+	return e.generation - e.improvement
+}
+
+// Diversity returns the genotypic diversity of the population, as the mean over every position of the genome
+// values (see Vector) of their standard deviation across the population. Only the positions shared by all the
+// genomes are considered. It is zero when all the individuals are identical, and NaN if G does not implement Vector
+func (e *Engine[G]) Diversity() float64 {
+	if len(e.Population) == 0 {
+		return 0.
+	}
+
+	vectors := make([][]float64, len(e.Population))
+	length := -1
+
+	for i, phenotype := range e.Population {
+		vector, ok := any(phenotype.Genome).(Vector)
+		if !ok {
+			return math.NaN()
+		}
+
+		vectors[i] = vector.Values()
+
+		if length < 0 || len(vectors[i]) < length {
+			length = len(vectors[i])
+		}
+	}
+
+	if length == 0 {
+		return 0.
+	}
+
+	sum := 0.
+
+	for j := 0; j < length; j++ {
+		mean, variance := 0., 0.
+
+		for _, vector := range vectors {
+			mean += vector[j]
+		}
+
+		mean /= float64(len(vectors))
+
+		for _, vector := range vectors {
+			variance += math.Pow(vector[j]-mean, 2)
+		}
+
+		sum += math.Sqrt(variance / float64(len(vectors)))
+	}
+
+	return sum / float64(length)
+}
+
+// Better reports whether fitness a is strictly better than fitness b according to Direction
+func (e *Engine[G]) Better(a, b float64) bool {
+	return e.Direction.Better(a, b)
+}
+
+// Best returns the individual of the population with the best fitness according to Direction
+func (e *Engine[G]) Best() Phenotype[G] {
 	best := e.Population[0]
 
-	for i := range e.Population[1:] {
-		if e.Population[i].Fitness > best.Fitness {
-			best = e.Population[i]
+	for _, phenotype := range e.Population[1:] {
+		if e.Direction.Better(phenotype.Fitness, best.Fitness) {
+			best = phenotype
 		}
 	}
 
 	return best
 }
 
-func (e *Engine) Worst() Phenotype {
+// Worst returns the individual of the population with the worst fitness according to Direction
+func (e *Engine[G]) Worst() Phenotype[G] {
 	worst := e.Population[0]
 
-	for i := range e.Population[1:] {
-		if e.Population[i].Fitness < worst.Fitness {
-			worst = e.Population[i]
+	for _, phenotype := range e.Population[1:] {
+		if e.Direction.Better(worst.Fitness, phenotype.Fitness) {
+			worst = phenotype
 		}
 	}
 
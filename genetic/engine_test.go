@@ -0,0 +1,333 @@
+package genetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func sum(c Chromosome) float64 {
+	fitness := 0.
+
+	for _, gene := range c.Genes {
+		for _, value := range gene.Sequence {
+			fitness += value
+		}
+	}
+
+	return fitness
+}
+
+func newTestEngine() *Engine[Chromosome] {
+	return &Engine[Chromosome]{Configuration: Configuration[Chromosome]{
+		PopulationSize: 20,
+		MaxAge:         5,
+		Selection:      TournamentSelection{Size: 3},
+		Crossover:      UniformCrossover{},
+		Mutation:       Gaussian{Probability: .1, Std: .1},
+		Elitism:        .1,
+		Iterations:     50,
+		Init: func(e *Engine[Chromosome]) {
+			for i := range e.Population {
+				e.Population[i].Genome = NewChromosome(8, 2)
+
+				for j := range e.Population[i].Genome.Genes {
+					e.Population[i].Genome.Genes[j].Randomize(e.Rand())
+				}
+			}
+		},
+		Evaluator: sum,
+	}}
+}
+
+func TestEngine_Run(t *testing.T) {
+	engine := newTestEngine()
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Completed {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Completed)
+	}
+
+	if result.Generations != engine.Iterations {
+		t.Errorf("result.Generations = %d, want %d", result.Generations, engine.Iterations)
+	}
+}
+
+func TestEngine_RunCanceled(t *testing.T) {
+	engine := newTestEngine()
+	engine.Iterations = int(^uint(0) >> 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	engine.Observer = func(i int, e *Engine[Chromosome]) {
+		if i == 9 {
+			cancel()
+		}
+	}
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Canceled {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Canceled)
+	}
+
+	if result.Generations != 10 {
+		t.Errorf("result.Generations = %d, want 10", result.Generations)
+	}
+}
+
+func TestEngine_RunDeadline(t *testing.T) {
+	engine := newTestEngine()
+	engine.Iterations = int(^uint(0) >> 1)
+
+	// a slow evaluator aborting as soon as the context is done
+	engine.ContextEvaluator = func(ctx context.Context, c Chromosome) (float64, error) {
+		select {
+		case <-ctx.Done():
+			return 0., ctx.Err()
+		case <-time.After(time.Millisecond):
+			return sum(c), nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != DeadlineExceeded {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, DeadlineExceeded)
+	}
+
+	if len(engine.Population) != engine.PopulationSize {
+		t.Errorf("len(engine.Population) = %d, want %d", len(engine.Population), engine.PopulationSize)
+	}
+}
+
+func TestEngine_Stop(t *testing.T) {
+	engine := newTestEngine()
+	engine.Observer = func(i int, e *Engine[Chromosome]) {
+		e.Stop()
+	}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Stopped {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Stopped)
+	}
+
+	if result.Generations != 1 {
+		t.Errorf("result.Generations = %d, want 1", result.Generations)
+	}
+}
+
+func TestEngine_RunSeed(t *testing.T) {
+	run := func() (Result[Chromosome], []Phenotype[Chromosome]) {
+		engine := newTestEngine()
+		engine.Seed = 42
+
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result, engine.Population
+	}
+
+	r1, p1 := run()
+	r2, p2 := run()
+
+	if r1.Seed != 42 {
+		t.Errorf("result.Seed = %d, want 42", r1.Seed)
+	}
+
+	if r1.Best.Fitness != r2.Best.Fitness {
+		t.Errorf("result.Best.Fitness = %f, want %f", r2.Best.Fitness, r1.Best.Fitness)
+	}
+
+	for i := range p1 {
+		for j := range p1[i].Genome.Genes {
+			for k := range p1[i].Genome.Genes[j].Sequence {
+				if p1[i].Genome.Genes[j].Sequence[k] != p2[i].Genome.Genes[j].Sequence[k] {
+					t.Fatalf("population[%d].Genes[%d].Sequence[%d] = %f, want %f", i, j, k,
+						p2[i].Genome.Genes[j].Sequence[k], p1[i].Genome.Genes[j].Sequence[k])
+				}
+			}
+		}
+	}
+}
+
+func TestConfiguration_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Configuration[Chromosome])
+	}{
+		{"population size", func(c *Configuration[Chromosome]) { c.PopulationSize = 0 }},
+		{"max age", func(c *Configuration[Chromosome]) { c.MaxAge = -1 }},
+		{"elitism", func(c *Configuration[Chromosome]) { c.Elitism = 1.5 }},
+		{"tournament size", func(c *Configuration[Chromosome]) { c.Selection = TournamentSelection{Size: 21} }},
+		{"crossover", func(c *Configuration[Chromosome]) { c.Crossover = nil }},
+		{"init", func(c *Configuration[Chromosome]) { c.Init = nil }},
+		{"evaluator", func(c *Configuration[Chromosome]) { c.Evaluator = nil }},
+		{"in-flight", func(c *Configuration[Chromosome]) { c.InFlight = 2 }},
+	}
+
+	if err := newTestEngine().Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	for _, test := range tests {
+		engine := newTestEngine()
+		test.modify(&engine.Configuration)
+
+		if err := engine.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want error", test.name)
+		}
+
+		if _, err := engine.Run(context.Background()); err == nil {
+			t.Errorf("%s: Run() error = nil, want error", test.name)
+		}
+	}
+}
+
+func TestEngine_RunInvalidLengths(t *testing.T) {
+	for _, lengths := range [][2]int{{0, 2}, {8, -1}} {
+		engine := newTestEngine()
+		engine.Init = func(e *Engine[Chromosome]) {
+			for i := range e.Population {
+				e.Population[i].Genome = NewChromosome(lengths[0], lengths[1])
+			}
+		}
+
+		if _, err := engine.Run(context.Background()); err == nil {
+			t.Errorf("%v: Run() error = nil, want error", lengths)
+		}
+	}
+}
+
+type failingCrossover struct{}
+
+func (f failingCrossover) Cross([]Chromosome, *rand.Rand) ([]Chromosome, error) {
+	return nil, errors.New("failure")
+}
+
+func (f failingCrossover) Children() int {
+	return 2
+}
+
+func TestEngine_RunError(t *testing.T) {
+	engine := newTestEngine()
+	engine.Crossover = failingCrossover{}
+
+	if _, err := engine.Run(context.Background()); err == nil {
+		t.Errorf("Run() error = nil, want error")
+	}
+
+	engine = newTestEngine()
+	engine.Evaluator = func(c Chromosome) float64 {
+		if c.Genes[0].Sequence[0] > .5 {
+			panic("failure")
+		}
+
+		return sum(c)
+	}
+
+	if _, err := engine.Run(context.Background()); err == nil {
+		t.Errorf("Run() error = nil, want error")
+	}
+}
+
+func TestEngine_RunMinimize(t *testing.T) {
+	engine := newTestEngine()
+	engine.Direction = Minimize
+	engine.Terminator = TargetFitness{Fitness: 2}
+	engine.Iterations = int(^uint(0) >> 1)
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Best.Fitness > 2 {
+		t.Errorf("result.Best.Fitness = %f, want <= 2", result.Best.Fitness)
+	}
+
+	if best, worst := engine.Best(), engine.Worst(); best.Fitness > worst.Fitness {
+		t.Errorf("engine.Best().Fitness = %f > engine.Worst().Fitness = %f", best.Fitness, worst.Fitness)
+	}
+}
+
+// TestEngine_RunWorkers checks that the outcome of a run does not depend on the size of the worker pool
+func TestEngine_RunWorkers(t *testing.T) {
+	run := func(workers int) Result[Chromosome] {
+		engine := newTestEngine()
+		engine.Workers = workers
+		engine.Seed = 1
+
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// timings are the only expected difference
+		result.Elapsed = 0
+
+		for i := range result.History {
+			result.History[i].Elapsed = 0
+		}
+
+		return result
+	}
+
+	if single, pool := run(1), run(8); !reflect.DeepEqual(single, pool) {
+		t.Errorf("Run() with 8 workers = %+v, want %+v as with 1 worker", pool, single)
+	}
+
+	engine := newTestEngine()
+	engine.Workers = -1
+
+	if _, err := engine.Run(context.Background()); err == nil {
+		t.Errorf("Run() error = nil, want error for negative workers")
+	}
+}
+
+// BenchmarkEngine_Run compares pools of different sizes, a pool as large as the population behaves like spawning a
+// goroutine for every task
+func BenchmarkEngine_Run(b *testing.B) {
+	const size = 1000
+
+	for _, workers := range []int{1, runtime.GOMAXPROCS(0), size} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				engine := newTestEngine()
+				engine.PopulationSize = size
+				engine.Iterations = 10
+				engine.Workers = workers
+				engine.Seed = 1
+
+				if _, err := engine.Run(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package ge
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Codons is the genome of grammatical evolution, a variable-length sequence of non-negative integer codons
+type Codons []int
+
+// RandomCodons returns codons whose number is drawn uniformly from lengths, whose Max must be set, and whose values
+// are drawn uniformly from [0, codonRange) using r
+func RandomCodons(lengths genetic.Lengths, codonRange int, r *rand.Rand) Codons {
+	codons := make(Codons, lengths.Min+r.Intn(lengths.Max-lengths.Min+1))
+
+	for i := range codons {
+		codons[i] = r.Intn(codonRange)
+	}
+
+	return codons
+}
+
+// Clone the receiver codons
+func (c Codons) Clone() Codons {
+	return append(Codons(nil), c...)
+}
+
+// Values returns the codons of the receiver
+func (c Codons) Values() []float64 {
+	values := make([]float64, len(c))
+
+	for i, codon := range c {
+		values[i] = float64(codon)
+	}
+
+	return values
+}
+
+// Hash returns the FNV-1a hash of the receiver codons
+func (c Codons) Hash() uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, codon := range c {
+		binary.LittleEndian.PutUint64(buffer, uint64(codon))
+		hash.Write(buffer)
+	}
+
+	return hash.Sum64()
+}
+
+// Equal reports whether the receiver and other have the same codons in the same order
+func (c Codons) Equal(other Codons) bool {
+	if len(c) != len(other) {
+		return false
+	}
+
+	for i := range c {
+		if c[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Init returns an Init function creating random codons in [0, codonRange), whose number is drawn from lengths
+func Init(lengths genetic.Lengths, codonRange int) func(*genetic.Engine[Codons]) {
+	return func(e *genetic.Engine[Codons]) {
+		for i := range e.Population {
+			e.Population[i].Genome = RandomCodons(lengths, codonRange, e.Rand())
+		}
+	}
+}
+
+// CodonMutation replaces every codon, with the given Probability, by a value drawn uniformly from [0, Range)
+type CodonMutation struct {
+	Range       int
+	Probability float64
+}
+
+func (m CodonMutation) Mutate(codons Codons, r *rand.Rand) Codons {
+	for i := range codons {
+		if r.Float64() < m.Probability {
+			codons[i] = r.Intn(m.Range)
+		}
+	}
+
+	return codons
+}
+
+// RippleCrossover is the one-point crossover of grammatical evolution: it cuts each parent at its own random point and
+// swaps the tails, so children can be longer or shorter than their parents. The cuts are drawn by Lengths.Cuts, as
+// genetic.CutAndSpliceCrossover does, so the children satisfy Lengths
+type RippleCrossover struct {
+	genetic.Lengths
+}
+
+func (c RippleCrossover) Cross(parents []Codons, r *rand.Rand) ([]Codons, error) {
+	if len(parents) != 2 {
+		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	mother, father := parents[0], parents[1]
+
+	i, j, err := c.Cuts(len(mother), len(father), r)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Codons{
+		append(mother[:i].Clone(), father[j:]...),
+		append(father[:j].Clone(), mother[i:]...),
+	}, nil
+}
+
+func (c RippleCrossover) Children() int {
+	return 2
+}
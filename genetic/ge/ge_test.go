@@ -0,0 +1,198 @@
+package ge
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+const expressions = `
+# arithmetic expressions
+<expr> ::= <expr> <op> <expr> | "(" <expr> ")"
+         | <var>
+<op>   ::= + | '*' | " - "
+<var>  ::= x | y
+`
+
+func TestParseBNF(t *testing.T) {
+	g, err := ParseBNF(strings.NewReader(expressions))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Start != "expr" {
+		t.Errorf("g.Start = %s, want expr", g.Start)
+	}
+
+	for name, want := range map[string]int{"expr": 3, "op": 3, "var": 2} {
+		if len(g.Rules[name]) != want {
+			t.Errorf("len(g.Rules[%s]) = %d, want %d", name, len(g.Rules[name]), want)
+		}
+	}
+
+	want := Production{{Name: "(", Terminal: true}, {Name: "expr"}, {Name: ")", Terminal: true}}
+	if got := g.Rules["expr"][1]; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("g.Rules[expr][1] = %v, want %v", got, want)
+	}
+
+	if got := g.Rules["op"][2][0].Name; got != " - " {
+		t.Errorf("g.Rules[op][2] = %q, want %q", got, " - ")
+	}
+
+	invalid := []string{
+		"",
+		"x | y",
+		"<a> ::= <b>",
+		"<a> ::= a <a>",
+		"<a> ::= \"a",
+		"<a> ::= < | a",
+		"a> ::= a",
+	}
+
+	for _, grammar := range invalid {
+		if _, err := ParseBNF(strings.NewReader(grammar)); err == nil {
+			t.Errorf("ParseBNF(%q) error = nil, want error", grammar)
+		}
+	}
+}
+
+func TestGrammar_Map(t *testing.T) {
+	g, err := ParseBNF(strings.NewReader(expressions + "<list> ::= a <list> | \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		codons []int
+		wraps  int
+		want   string
+		used   int
+		err    error
+	}{
+		{[]int{0, 2, 0, 0, 2, 1}, 0, "x+y", 6, nil},
+		{[]int{4, 2, 1}, 0, "(y)", 3, nil},
+		{[]int{2}, 0, "", 1, ErrIncomplete},
+		{[]int{2}, 1, "x", 2, nil},
+		{[]int{0}, 3, "", 4, ErrIncomplete},
+		{nil, 3, "", 0, ErrIncomplete},
+	}
+
+	for _, test := range tests {
+		d, err := g.Map(test.codons, test.wraps)
+
+		if err != test.err || d.Output != test.want || d.Codons != test.used {
+			t.Errorf("Map(%v, %d) = %+v, %v, want %q, %d codons", test.codons, test.wraps, d, err, test.want, test.used)
+		}
+	}
+
+	g.Start = "list"
+
+	if d, err := g.Map([]int{0, 0, 1}, 0); err != nil || d.Output != "aa" {
+		t.Errorf("Map() = %+v, %v, want aa", d, err)
+	}
+}
+
+func TestCodonMutation_Mutate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	codons := CodonMutation{Range: 4, Probability: 1}.Mutate(make(Codons, 100), r)
+
+	for _, codon := range codons {
+		if codon < 0 || codon >= 4 {
+			t.Fatalf("Mutate() = %v, want codons in [0, 4)", codons)
+		}
+	}
+
+	if codons.Equal(make(Codons, 100)) {
+		t.Errorf("Mutate() = %v, want mutated codons", codons)
+	}
+
+	if codons := (CodonMutation{Range: 4}).Mutate(Codons{7, 7}, r); !codons.Equal(Codons{7, 7}) {
+		t.Errorf("Mutate() = %v, want [7 7]", codons)
+	}
+}
+
+func TestRippleCrossover_Cross(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	crossover := RippleCrossover{genetic.Lengths{Min: 2, Max: 6}}
+	mother, father := Codons{1, 1, 1, 1}, Codons{2, 2, 2}
+
+	for k := 0; k < 100; k++ {
+		children, err := crossover.Cross([]Codons{mother, father}, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(children[0])+len(children[1]) != len(mother)+len(father) {
+			t.Fatalf("Cross() = %v, want %d codons", children, len(mother)+len(father))
+		}
+
+		for _, child := range children {
+			if len(child) < 2 || len(child) > 6 {
+				t.Fatalf("Cross() = %v, want children of [2, 6] codons", children)
+			}
+		}
+	}
+
+	if !mother.Equal(Codons{1, 1, 1, 1}) || !father.Equal(Codons{2, 2, 2}) {
+		t.Errorf("Cross() modified the parents: %v, %v", mother, father)
+	}
+
+	if _, err := crossover.Cross([]Codons{{1}}, r); err == nil {
+		t.Error("Cross() error = nil, want error")
+	}
+}
+
+// TestEngine_RunGrammar evolves a word of the language of the grammar with the codon operators
+func TestEngine_RunGrammar(t *testing.T) {
+	const target = "abcab"
+
+	g, err := ParseBNF(strings.NewReader("<word> ::= <char> | <char> <word>\n<char> ::= a | b | c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapper := Mapper{Grammar: g, Wraps: 2}
+	lengths := genetic.Lengths{Min: 5, Max: 30}
+
+	similarity := func(word string) float64 {
+		score := -float64(len(word) - len(target))
+		if score > 0 {
+			score = -score
+		}
+
+		for i := 0; i < len(word) && i < len(target); i++ {
+			if word[i] == target[i] {
+				score++
+			}
+		}
+
+		return score
+	}
+
+	engine := &genetic.Engine[Codons]{Configuration: genetic.Configuration[Codons]{
+		PopulationSize: 100,
+		MaxAge:         10,
+		Selection:      genetic.TournamentSelection{Size: 3},
+		Crossover:      RippleCrossover{Lengths: lengths},
+		Mutation:       CodonMutation{Range: 256, Probability: .05},
+		Elitism:        .1,
+		Iterations:     200,
+		Init:           Init(lengths, 256),
+		Evaluator:      mapper.Evaluator(similarity, -100),
+		Terminator:     genetic.TargetFitness{Fitness: float64(len(target))},
+		Seed:           1,
+	}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d, err := mapper.Map(result.Best.Genome); err != nil || d.Output != target {
+		t.Errorf("result.Best = %q, %v, want %q", d.Output, err, target)
+	}
+}
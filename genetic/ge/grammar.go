@@ -0,0 +1,277 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+// Package ge implements grammatical evolution: genomes are variable-length sequences of integer codons that choose,
+// one after the other, the productions of a BNF grammar, so every genome maps to a string of the language of the
+// grammar which is then evaluated. Codons evolve with the Engine and the selections of the genetic package, the
+// codon operators follow its Mutator and Crossover interfaces and share the length constraints of its
+// variable-length chromosomes.
+package ge
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Symbol of a grammar. The Name of a non-terminal is the one between angle brackets, the one of a terminal is
+// its text
+type Symbol struct {
+	Name     string
+	Terminal bool
+}
+
+// A Production is one of the alternatives a non-terminal can be expanded to
+type Production []Symbol
+
+// A Grammar in Backus-Naur form
+type Grammar struct {
+	// Start is the non-terminal every derivation starts from
+	Start string
+	Rules map[string][]Production
+}
+
+// ParseBNF reads a grammar in Backus-Naur form, as in
+//
+//	# comment
+//	<expr> ::= <expr> <op> <expr> | "(" <expr> ")" | <var>
+//	<op>   ::= + | - | *
+//	<var>  ::= x
+//	         | y
+//
+// A rule may continue on the following lines, the left-hand side of the first rule is the start symbol. Whitespace
+// separates symbols and is not part of the output: quoted terminals may contain it, as well as < and |, and double
+// quoted ones are unquoted as Go strings. Every non-terminal must be defined and able to derive a string
+func ParseBNF(r io.Reader) (*Grammar, error) {
+	g := &Grammar{Rules: make(map[string][]Production)}
+
+	// line of the definition and of the first reference of every non-terminal
+	defined, referenced := make(map[string]int), make(map[string]int)
+
+	var lhs string
+
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if i := strings.Index(line, "::="); i >= 0 && strings.HasPrefix(line, "<") {
+			name := strings.TrimSpace(line[:i])
+
+			if len(name) < 3 || !strings.HasSuffix(name, ">") || strings.ContainsAny(name[1:len(name)-1], "<>") {
+				return nil, fmt.Errorf("line %d: invalid non-terminal %s", n, name)
+			}
+
+			lhs, line = name[1:len(name)-1], line[i+3:]
+
+			if g.Start == "" {
+				g.Start = lhs
+			}
+
+			if _, ok := defined[lhs]; !ok {
+				defined[lhs] = n
+			}
+
+			// a new rule starts with an empty production, a continuation line extends the last one
+			g.Rules[lhs] = append(g.Rules[lhs], nil)
+		} else if lhs == "" {
+			return nil, fmt.Errorf("line %d: missing rule", n)
+		}
+
+		alternatives, err := tokenize(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+
+		rules := g.Rules[lhs]
+
+		for k, alternative := range alternatives {
+			if k > 0 {
+				rules = append(rules, Production{})
+			}
+
+			for _, symbol := range alternative {
+				if _, ok := referenced[symbol.Name]; !symbol.Terminal && !ok {
+					referenced[symbol.Name] = n
+				}
+
+				rules[len(rules)-1] = append(rules[len(rules)-1], symbol)
+			}
+		}
+
+		g.Rules[lhs] = rules
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if g.Start == "" {
+		return nil, errors.New("missing rules")
+	}
+
+	for name, n := range referenced {
+		if _, ok := defined[name]; !ok {
+			return nil, fmt.Errorf("line %d: undefined non-terminal <%s>", n, name)
+		}
+	}
+
+	if name, ok := g.unproductive(); ok {
+		return nil, fmt.Errorf("line %d: non-terminal <%s> derives no string", defined[name], name)
+	}
+
+	return g, nil
+}
+
+// LoadBNF reads a grammar from the file at path, see ParseBNF
+func LoadBNF(path string) (*Grammar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ParseBNF(file)
+}
+
+// tokenize splits the right-hand side of a rule in alternatives. Every separator starts a new alternative, so a
+// leading one yields an empty alternative first
+func tokenize(line string) ([][]Symbol, error) {
+	alternatives := [][]Symbol{{}}
+
+	add := func(symbol Symbol) {
+		alternatives[len(alternatives)-1] = append(alternatives[len(alternatives)-1], symbol)
+	}
+
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			alternatives = append(alternatives, []Symbol{})
+			i++
+		case c == '<':
+			end := strings.IndexByte(line[i:], '>')
+			if end < 2 {
+				return nil, fmt.Errorf("invalid non-terminal at column %d", i+1)
+			}
+
+			add(Symbol{Name: line[i+1 : i+end]})
+			i += end + 1
+		case c == '"' || c == '\'':
+			end := i + 1
+			for ; end < len(line) && line[end] != c; end++ {
+				if c == '"' && line[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated string at column %d", i+1)
+			}
+
+			text := line[i+1 : end]
+
+			if c == '"' {
+				var err error
+				if text, err = strconv.Unquote(line[i : end+1]); err != nil {
+					return nil, fmt.Errorf("invalid string at column %d: %v", i+1, err)
+				}
+			}
+
+			add(Symbol{Name: text, Terminal: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(line) && !strings.ContainsRune(" \t|<\"'", rune(line[end])) {
+				end++
+			}
+
+			add(Symbol{Name: line[i:end], Terminal: true})
+			i = end
+		}
+	}
+
+	return alternatives, nil
+}
+
+// unproductive returns a non-terminal that cannot derive a string of terminals, if any. A mapping expanding only
+// non-terminals with a single production, which consume no codon, is then bound to terminate
+func (g *Grammar) unproductive() (string, bool) {
+	productive := make(map[string]bool)
+
+	for changed := true; changed; {
+		changed = false
+
+		for name, productions := range g.Rules {
+			if productive[name] {
+				continue
+			}
+
+			for _, production := range productions {
+				if g.derives(production, productive) {
+					productive[name], changed = true, true
+					break
+				}
+			}
+		}
+	}
+
+	// sorted for a deterministic error
+	var names []string
+	for name := range g.Rules {
+		if !productive[name] {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "", false
+	}
+
+	sort.Strings(names)
+
+	return names[0], true
+}
+
+// derives reports whether all the symbols of production are terminals or productive non-terminals
+func (g *Grammar) derives(production Production, productive map[string]bool) bool {
+	for _, symbol := range production {
+		if !symbol.Terminal && !productive[symbol.Name] {
+			return false
+		}
+	}
+
+	return true
+}
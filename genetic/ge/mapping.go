@@ -0,0 +1,121 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package ge
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrIncomplete is returned when the codons, wrapping included, run out before the derivation is complete
+var ErrIncomplete = errors.New("incomplete derivation: codons exhausted")
+
+// A Derivation is the outcome of a mapping
+type Derivation struct {
+	Output string
+	// Codons is the number of codons consumed, reused ones included
+	Codons int
+}
+
+// Map derives a string from the start symbol expanding, one after the other, the leftmost non-terminal with the
+// production chosen by the next codon modulo the number of productions. Non-terminals with a single production
+// consume no codon. Once exhausted, codons are read again from the first one, at most wraps times. Codons must not
+// be negative
+func (g *Grammar) Map(codons []int, wraps int) (Derivation, error) {
+	var output strings.Builder
+	var d Derivation
+
+	stack := []Symbol{{Name: g.Start}}
+
+	for len(stack) > 0 {
+		symbol := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if symbol.Terminal {
+			output.WriteString(symbol.Name)
+			continue
+		}
+
+		productions := g.Rules[symbol.Name]
+		production := productions[0]
+
+		if len(productions) > 1 {
+			if len(codons) == 0 || d.Codons == len(codons)*(wraps+1) {
+				return d, ErrIncomplete
+			}
+
+			production = productions[codons[d.Codons%len(codons)]%len(productions)]
+			d.Codons++
+		}
+
+		for i := len(production) - 1; i >= 0; i-- {
+			stack = append(stack, production[i])
+		}
+	}
+
+	d.Output = output.String()
+
+	return d, nil
+}
+
+// A Mapper maps codons to strings of the language of Grammar
+type Mapper struct {
+	Grammar *Grammar
+	// Wraps is the maximum number of times the codons are read again once exhausted
+	Wraps int
+}
+
+// Map derives a string from codons, see Grammar.Map
+func (m Mapper) Map(codons Codons) (Derivation, error) {
+	return m.Grammar.Map(codons, m.Wraps)
+}
+
+// Evaluator returns an evaluator scoring with evaluate the string codons map to. Codons whose derivation is
+// incomplete score invalid, which should be worse than any valid fitness
+func (m Mapper) Evaluator(evaluate func(string) float64, invalid float64) func(Codons) float64 {
+	return func(codons Codons) float64 {
+		d, err := m.Map(codons)
+		if err != nil {
+			return invalid
+		}
+
+		return evaluate(d.Output)
+	}
+}
+
+// ContextEvaluator is like Evaluator for evaluations that can fail, as running the program codons map to. Failures
+// abort the evolution, so evaluate should rather score invalid outputs poorly
+func (m Mapper) ContextEvaluator(evaluate func(context.Context, string) (float64, error),
+	invalid float64) func(context.Context, Codons) (float64, error) {
+	return func(ctx context.Context, codons Codons) (float64, error) {
+		d, err := m.Map(codons)
+		if err != nil {
+			return invalid, nil
+		}
+
+		return evaluate(ctx, d.Output)
+	}
+}
@@ -34,18 +34,29 @@ type Gene struct {
 	Sequence []float64
 }
 
-// NewGene returns a new 'zero' Gene
-func NewGene(length int) Gene {
+// MakeGene returns a new 'zero' Gene, or an error if length is negative
+func MakeGene(length int) (Gene, error) {
 	if length < 0 {
-		panic(fmt.Sprintf("invalid argument: length = %d (must be greater than zero)", length))
+		return Gene{}, fmt.Errorf("invalid gene length: %d (must not be negative)", length)
 	}
 
-	return Gene{make([]float64, length)}
+	return Gene{make([]float64, length)}, nil
+}
+
+// NewGene is like MakeGene but panics if length is negative, it is meant for lengths known to be valid
+func NewGene(length int) Gene {
+	gene, err := MakeGene(length)
+	if err != nil {
+		panic(err)
+	}
+
+	return gene
 }
 
-func (g *Gene) Randomize() {
+// Randomize sets the receiver values drawing them uniformly from [0, 1) using r
+func (g *Gene) Randomize(r *rand.Rand) {
 	for i := range g.Sequence {
-		g.Sequence[i] = rand.Float64()
+		g.Sequence[i] = r.Float64()
 	}
 }
 
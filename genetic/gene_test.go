@@ -27,3 +27,9 @@ func TestGene_Clone(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeGene(t *testing.T) {
+	if _, err := MakeGene(-1); err == nil {
+		t.Errorf("MakeGene(-1) error = nil, want error")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+// A Genome is the genetic representation evolved by an Engine. Besides cloning itself, a genome can implement
+// Vector to have its diversity measured, and Hash and Equal (see Cache) to have its evaluations cached
+type Genome[G any] interface {
+	Clone() G
+}
+
+// A Vector exposes the numeric values of a genome, they are used to measure the genotypic diversity of a population
+type Vector interface {
+	Values() []float64
+}
+
+// Score collects the evaluation of an individual, it is what selection and replacement operators see of it. In
+// multi-objective optimisation Objectives holds the scores of the individual, Fitness its first objective, Rank the
+// index of its Pareto front and Crowding its crowding distance within the front
+type Score struct {
+	Fitness    float64
+	Age        int
+	Objectives []float64
+	Rank       int
+	Crowding   float64
+}
+
+// Phenotype encapsulates a genome with its score
+type Phenotype[G any] struct {
+	Genome G
+	Score
+}
+
+// Direction of the optimisation, it tells whether a greater fitness is better or worse
+type Direction int
+
+const (
+	Maximize Direction = iota
+	Minimize
+)
+
+// Better reports whether fitness a is strictly better than fitness b
+func (d Direction) Better(a, b float64) bool {
+	if d == Minimize {
+		return a < b
+	}
+
+	return a > b
+}
+
+func (d Direction) String() string {
+	if d == Minimize {
+		return "minimize"
+	}
+
+	return "maximize"
+}
+
+// sort.Interface implementation sorting a Phenotype slice from the best to the worst
+type ranking[G any] struct {
+	phenotypes []Phenotype[G]
+	direction  Direction
+}
+
+func (r ranking[G]) Len() int {
+	return len(r.phenotypes)
+}
+
+func (r ranking[G]) Swap(i, j int) {
+	r.phenotypes[i], r.phenotypes[j] = r.phenotypes[j], r.phenotypes[i]
+}
+
+func (r ranking[G]) Less(i, j int) bool {
+	return r.direction.Better(r.phenotypes[i].Fitness, r.phenotypes[j].Fitness)
+}
+
+// scores returns the scores of population, in the same order
+func scores[G any](population []Phenotype[G]) []Score {
+	scores := make([]Score, len(population))
+
+	for i := range population {
+		scores[i] = population[i].Score
+	}
+
+	return scores
+}
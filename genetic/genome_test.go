@@ -0,0 +1,89 @@
+package genetic
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// point is a genome that is not a Chromosome, nor a Vector
+type point struct {
+	X, Y int
+}
+
+func (p point) Clone() point {
+	return p
+}
+
+type midpoint struct{}
+
+func (midpoint) Cross(parents []point, r *rand.Rand) ([]point, error) {
+	return []point{{(parents[0].X + parents[1].X) / 2, (parents[0].Y + parents[1].Y) / 2}, parents[r.Intn(2)]}, nil
+}
+
+func (midpoint) Children() int {
+	return 2
+}
+
+type step struct{}
+
+func (step) Mutate(p point, r *rand.Rand) point {
+	p.X += r.Intn(3) - 1
+	p.Y += r.Intn(3) - 1
+
+	return p
+}
+
+func newPointEngine() *Engine[point] {
+	return &Engine[point]{Configuration: Configuration[point]{
+		PopulationSize: 20,
+		MaxAge:         5,
+		Direction:      Minimize,
+		Selection:      TournamentSelection{Size: 3},
+		Crossover:      midpoint{},
+		Mutation:       step{},
+		Elitism:        .1,
+		Iterations:     100,
+		Init: func(e *Engine[point]) {
+			for i := range e.Population {
+				e.Population[i].Genome = point{e.Rand().Intn(100) - 50, e.Rand().Intn(100) - 50}
+			}
+		},
+		Evaluator: func(p point) float64 {
+			return math.Abs(float64(p.X-3)) + math.Abs(float64(p.Y+2))
+		},
+		Seed: 1,
+	}}
+}
+
+func TestEngine_RunGenome(t *testing.T) {
+	engine := newPointEngine()
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (point{3, -2}); result.Best.Genome != want {
+		t.Errorf("result.Best.Genome = %v, want %v", result.Best.Genome, want)
+	}
+
+	if diversity := engine.Diversity(); !math.IsNaN(diversity) {
+		t.Errorf("engine.Diversity() = %f, want NaN", diversity)
+	}
+
+	engine.Cache = NewCache[point](10)
+
+	if err := engine.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for a cache of genomes without Hash")
+	}
+
+	engine = newPointEngine()
+	engine.Terminator = Any{Timeout{Duration: time.Minute}, MinDiversity{Diversity: .1}}
+
+	if err := engine.Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for a minimum diversity of genomes without Values")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package gp
+
+import (
+	"math"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Evaluator returns an evaluator running a tree on every row of inputs, whose values are bound to the variables of
+// the set by position, and scoring the outputs with score
+func (s *Set) Evaluator(inputs [][]float64, score func(outputs []float64) float64) func(Tree) float64 {
+	return func(tree Tree) float64 {
+		outputs := make([]float64, len(inputs))
+
+		for i, input := range inputs {
+			outputs[i] = s.Eval(tree, input)
+		}
+
+		return score(outputs)
+	}
+}
+
+// MeanSquaredError returns a score, to be minimized, measuring the mean squared error of the outputs against targets.
+// Outputs that are not finite score +Inf
+func MeanSquaredError(targets []float64) func(outputs []float64) float64 {
+	return func(outputs []float64) float64 {
+		sum := 0.
+
+		for i, output := range outputs {
+			if math.IsNaN(output) || math.IsInf(output, 0) {
+				return math.Inf(1)
+			}
+
+			sum += (output - targets[i]) * (output - targets[i])
+		}
+
+		return sum / float64(len(outputs))
+	}
+}
+
+// Parsimony wraps evaluate adding a pressure, proportional to coefficient, towards smaller trees: the fitness is
+// worsened by coefficient for every node according to direction
+func Parsimony(evaluate func(Tree) float64, coefficient float64, direction genetic.Direction) func(Tree) float64 {
+	if direction == genetic.Maximize {
+		coefficient = -coefficient
+	}
+
+	return func(tree Tree) float64 {
+		return evaluate(tree) + coefficient*float64(len(tree))
+	}
+}
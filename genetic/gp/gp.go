@@ -0,0 +1,334 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+// Package gp implements tree-based genetic programming on top of the genetic package: programs are expression trees
+// over a user-declared set of functions and terminals, evolved by a genetic.Engine[gp.Tree] with the operators of
+// this package and the selections of the genetic package.
+package gp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// A Function is an internal node of a tree, it computes its value from the values of its Arity children
+type Function struct {
+	Name  string
+	Arity int
+	Eval  func(args []float64) float64
+}
+
+// A Set declares the primitives trees are built from: Functions, Variables bound to the inputs of a program by
+// position and, if Constant is set, ephemeral random constants drawn by Constant when a terminal is created
+type Set struct {
+	Functions []Function
+	Variables []string
+	Constant  func(r *rand.Rand) float64
+}
+
+// Validate returns an error describing the first invalid primitive, if any
+func (s *Set) Validate() error {
+	if len(s.Functions) == 0 {
+		return errors.New("missing functions")
+	}
+
+	if len(s.Variables) == 0 && s.Constant == nil {
+		return errors.New("missing terminals")
+	}
+
+	for _, f := range s.Functions {
+		if f.Arity < 1 || f.Eval == nil {
+			return fmt.Errorf("invalid function %q: arity %d (must be greater than zero and Eval must be set)", f.Name, f.Arity)
+		}
+	}
+
+	return nil
+}
+
+// Kind tells what a node of a tree is
+type Kind int
+
+const (
+	FunctionNode Kind = iota
+	VariableNode
+	ConstantNode
+)
+
+// A Node of a tree. Index is the index of the function or of the variable within the Set, Value the value of a
+// constant
+type Node struct {
+	Kind  Kind
+	Index int
+	Arity int
+	Value float64
+}
+
+// A Tree is an expression tree stored as its nodes in prefix order, the root first. It does not reference its Set,
+// so it can be cloned, hashed and serialised on its own
+type Tree []Node
+
+// Clone the receiver tree
+func (t Tree) Clone() Tree {
+	return append(Tree(nil), t...)
+}
+
+// Hash returns the FNV-1a hash of the receiver nodes
+func (t Tree) Hash() uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, node := range t {
+		for _, value := range []uint64{uint64(node.Kind), uint64(node.Index), math.Float64bits(node.Value)} {
+			binary.LittleEndian.PutUint64(buffer, value)
+			hash.Write(buffer)
+		}
+	}
+
+	return hash.Sum64()
+}
+
+// Equal reports whether the receiver and other have the same nodes
+func (t Tree) Equal(other Tree) bool {
+	if len(t) != len(other) {
+		return false
+	}
+
+	for i := range t {
+		if t[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Size returns the number of nodes of the receiver
+func (t Tree) Size() int {
+	return len(t)
+}
+
+// Depth returns the length of the longest path from the root to a leaf, a single terminal has depth zero
+func (t Tree) Depth() int {
+	depth := 0
+
+	// pending holds, for every open function node, the number of children still to visit
+	var pending []int
+
+	for _, node := range t {
+		if len(pending) > depth {
+			depth = len(pending)
+		}
+
+		if node.Arity > 0 {
+			pending = append(pending, node.Arity)
+			continue
+		}
+
+		for len(pending) > 0 {
+			if pending[len(pending)-1]--; pending[len(pending)-1] > 0 {
+				break
+			}
+
+			pending = pending[:len(pending)-1]
+		}
+	}
+
+	return depth
+}
+
+// end returns the index following the subtree rooted at i
+func (t Tree) end(i int) int {
+	for open := 1; open > 0; i++ {
+		open += t[i].Arity - 1
+	}
+
+	return i
+}
+
+// Eval returns the value of tree for the given values of the variables
+func (s *Set) Eval(tree Tree, variables []float64) float64 {
+	// prefix order read backwards is postfix order with the arguments reversed
+	stack := make([]float64, 0, len(tree))
+
+	for i := len(tree) - 1; i >= 0; i-- {
+		node := tree[i]
+
+		switch node.Kind {
+		case VariableNode:
+			stack = append(stack, variables[node.Index])
+		case ConstantNode:
+			stack = append(stack, node.Value)
+		default:
+			args := make([]float64, node.Arity)
+
+			for j := range args {
+				args[j] = stack[len(stack)-1-j]
+			}
+
+			stack = append(stack[:len(stack)-node.Arity], s.Functions[node.Index].Eval(args))
+		}
+	}
+
+	return stack[0]
+}
+
+// Format returns tree in prefix notation, as (+ x (* 2 y))
+func (s *Set) Format(tree Tree) string {
+	var builder strings.Builder
+
+	s.format(&builder, tree, 0)
+
+	return builder.String()
+}
+
+func (s *Set) format(builder *strings.Builder, tree Tree, i int) int {
+	node := tree[i]
+
+	switch node.Kind {
+	case VariableNode:
+		builder.WriteString(s.Variables[node.Index])
+		return i + 1
+	case ConstantNode:
+		builder.WriteString(strconv.FormatFloat(node.Value, 'g', -1, 64))
+		return i + 1
+	}
+
+	builder.WriteString("(")
+	builder.WriteString(s.Functions[node.Index].Name)
+
+	i++
+
+	for k := 0; k < node.Arity; k++ {
+		builder.WriteString(" ")
+		i = s.format(builder, tree, i)
+	}
+
+	builder.WriteString(")")
+
+	return i
+}
+
+// function returns a node of a random function, of the given arity if not negative
+func (s *Set) function(arity int, r *rand.Rand) (Node, bool) {
+	var candidates []int
+
+	for i, f := range s.Functions {
+		if arity < 0 || f.Arity == arity {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Node{}, false
+	}
+
+	i := candidates[r.Intn(len(candidates))]
+
+	return Node{Kind: FunctionNode, Index: i, Arity: s.Functions[i].Arity}, true
+}
+
+// terminal returns a node of a random variable or a new ephemeral constant
+func (s *Set) terminal(r *rand.Rand) Node {
+	terminals := len(s.Variables)
+	if s.Constant != nil {
+		terminals++
+	}
+
+	if i := r.Intn(terminals); i < len(s.Variables) {
+		return Node{Kind: VariableNode, Index: i}
+	}
+
+	return Node{Kind: ConstantNode, Value: s.Constant(r)}
+}
+
+// terminals returns the number of terminal primitives
+func (s *Set) terminals() int {
+	if s.Constant != nil {
+		return len(s.Variables) + 1
+	}
+
+	return len(s.Variables)
+}
+
+// generate returns a random tree of at most the given depth. A full tree has all its leaves at that depth, a grown
+// one picks every node among all the primitives so its branches stop at random depths
+func (s *Set) generate(depth int, full bool, r *rand.Rand) Tree {
+	var tree Tree
+
+	leaf := float64(s.terminals()) / float64(s.terminals()+len(s.Functions))
+
+	var grow func(d int)
+	grow = func(d int) {
+		if d >= depth || (!full && r.Float64() < leaf) {
+			tree = append(tree, s.terminal(r))
+			return
+		}
+
+		node, _ := s.function(-1, r)
+		tree = append(tree, node)
+
+		for k := 0; k < node.Arity; k++ {
+			grow(d + 1)
+		}
+	}
+
+	grow(0)
+
+	return tree
+}
+
+// Full returns a random tree whose leaves are all at the given depth
+func (s *Set) Full(depth int, r *rand.Rand) Tree {
+	return s.generate(depth, true, r)
+}
+
+// Grow returns a random tree of at most the given depth
+func (s *Set) Grow(depth int, r *rand.Rand) Tree {
+	return s.generate(depth, false, r)
+}
+
+// RampedHalfAndHalf returns the i-th of n trees of a ramped half-and-half initialisation: depths are spread evenly
+// over [minDepth, maxDepth] and, for every depth, half of the trees are full and half are grown
+func (s *Set) RampedHalfAndHalf(i, n, minDepth, maxDepth int, r *rand.Rand) Tree {
+	depth := minDepth + i*(maxDepth-minDepth+1)/n
+
+	return s.generate(depth, i%2 == 0, r)
+}
+
+// Init returns an Init function creating the population with ramped half-and-half
+func Init(set *Set, minDepth, maxDepth int) func(*genetic.Engine[Tree]) {
+	return func(e *genetic.Engine[Tree]) {
+		for i := range e.Population {
+			e.Population[i].Genome = set.RampedHalfAndHalf(i, len(e.Population), minDepth, maxDepth, e.Rand())
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package gp
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+func newTestSet() *Set {
+	return &Set{
+		Functions: append(Arithmetic(), Sin),
+		Variables: []string{"x"},
+		Constant:  UniformConstant(-1, 1),
+	}
+}
+
+// valid reports whether tree is a single well formed expression
+func valid(tree Tree) bool {
+	open := 1
+
+	for _, node := range tree {
+		if open == 0 {
+			return false
+		}
+
+		open += node.Arity - 1
+	}
+
+	return open == 0
+}
+
+func TestSet_Validate(t *testing.T) {
+	if err := newTestSet().Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	invalid := []*Set{
+		{Variables: []string{"x"}},
+		{Functions: Arithmetic()},
+		{Functions: []Function{{Name: "f"}}, Variables: []string{"x"}},
+	}
+
+	for _, set := range invalid {
+		if err := set.Validate(); err == nil {
+			t.Errorf("Validate(%v) error = nil, want error", set)
+		}
+	}
+}
+
+func TestSet_Eval(t *testing.T) {
+	set := newTestSet()
+
+	// (- (* x x) (/ 3 0))
+	tree := Tree{
+		{Kind: FunctionNode, Index: 1, Arity: 2},
+		{Kind: FunctionNode, Index: 2, Arity: 2},
+		{Kind: VariableNode},
+		{Kind: VariableNode},
+		{Kind: FunctionNode, Index: 3, Arity: 2},
+		{Kind: ConstantNode, Value: 3},
+		{Kind: ConstantNode, Value: 0},
+	}
+
+	if got := set.Eval(tree, []float64{4}); got != 15 {
+		t.Errorf("Eval() = %f, want 15", got)
+	}
+
+	if got, want := set.Format(tree), "(- (* x x) (/ 3 0))"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+
+	if got := tree.Depth(); got != 2 {
+		t.Errorf("Depth() = %d, want 2", got)
+	}
+}
+
+func TestSet_RampedHalfAndHalf(t *testing.T) {
+	set := newTestSet()
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		tree := set.RampedHalfAndHalf(i, 100, 2, 6, r)
+
+		if !valid(tree) {
+			t.Fatalf("RampedHalfAndHalf() = %v, not a tree", tree)
+		}
+
+		if depth := tree.Depth(); depth > 6 || (i%2 == 0 && depth != 2+i*5/100) {
+			t.Errorf("RampedHalfAndHalf(%d).Depth() = %d", i, depth)
+		}
+	}
+}
+
+func TestOperators(t *testing.T) {
+	const maxDepth = 6
+
+	set := newTestSet()
+	r := rand.New(rand.NewSource(1))
+
+	crossover := SubtreeCrossover{FunctionProbability: .9, MaxDepth: maxDepth}
+	mutators := []genetic.Mutator[Tree]{
+		PointMutation{Set: set, Probability: .2},
+		SubtreeMutation{Set: set, Probability: 1, Depth: 3, MaxDepth: maxDepth},
+		HoistMutation{Probability: 1},
+	}
+
+	for n := 0; n < 200; n++ {
+		parents := []Tree{set.Grow(maxDepth, r), set.Full(3, r)}
+
+		children, err := crossover.Cross(parents, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, child := range children {
+			for _, mutator := range mutators {
+				child = mutator.Mutate(child, r)
+
+				if !valid(child) || child.Depth() > maxDepth {
+					t.Fatalf("%T: Mutate() = %s, not a tree within depth %d", mutator, set.Format(child), maxDepth)
+				}
+			}
+		}
+	}
+
+	if _, err := crossover.Cross([]Tree{set.Full(2, r)}, r); err == nil {
+		t.Errorf("Cross() error = nil, want error for a single parent")
+	}
+}
+
+func TestParsimony(t *testing.T) {
+	tree := newTestSet().Full(2, rand.New(rand.NewSource(1)))
+	zero := func(Tree) float64 { return 0 }
+
+	if got := Parsimony(zero, .5, genetic.Minimize)(tree); got != .5*float64(len(tree)) {
+		t.Errorf("Parsimony(Minimize) = %f, want %f", got, .5*float64(len(tree)))
+	}
+
+	if got := Parsimony(zero, .5, genetic.Maximize)(tree); got != -.5*float64(len(tree)) {
+		t.Errorf("Parsimony(Maximize) = %f, want %f", got, -.5*float64(len(tree)))
+	}
+}
+
+// TestEngine_RunRegression rediscovers x^2 + x from a few samples
+func TestEngine_RunRegression(t *testing.T) {
+	set := &Set{Functions: Arithmetic(), Variables: []string{"x"}}
+
+	var inputs [][]float64
+	var targets []float64
+
+	for x := -1.; x <= 1; x += .1 {
+		inputs = append(inputs, []float64{x})
+		targets = append(targets, x*x+x)
+	}
+
+	engine := &genetic.Engine[Tree]{Configuration: genetic.Configuration[Tree]{
+		PopulationSize: 200,
+		MaxAge:         10,
+		Direction:      genetic.Minimize,
+		Selection:      genetic.TournamentSelection{Size: 5},
+		Crossover:      SubtreeCrossover{FunctionProbability: .9, MaxDepth: 8},
+		Mutation:       SubtreeMutation{Set: set, Probability: .1, Depth: 2, MaxDepth: 8},
+		Elitism:        .05,
+		Iterations:     100,
+		Init:           Init(set, 2, 5),
+		Evaluator:      set.Evaluator(inputs, MeanSquaredError(targets)),
+		Terminator:     genetic.TargetFitness{Fitness: 1e-12},
+		Cache:          genetic.NewCache[Tree](1000),
+		Seed:           1,
+	}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != genetic.Terminated {
+		t.Errorf("result.Best = %s (%f), want x^2 + x", set.Format(result.Best.Genome), result.Best.Fitness)
+	}
+
+	if !math.IsNaN(engine.Diversity()) {
+		t.Errorf("Diversity() = %f, want NaN for trees", engine.Diversity())
+	}
+}
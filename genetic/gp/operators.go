@@ -0,0 +1,158 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package gp
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// point returns the index of a random node of tree: a function node with the given probability, if there is any,
+// otherwise a terminal
+func point(tree Tree, function float64, r *rand.Rand) int {
+	var functions, terminals []int
+
+	for i, node := range tree {
+		if node.Arity > 0 {
+			functions = append(functions, i)
+		} else {
+			terminals = append(terminals, i)
+		}
+	}
+
+	if len(functions) > 0 && r.Float64() < function {
+		return functions[r.Intn(len(functions))]
+	}
+
+	return terminals[r.Intn(len(terminals))]
+}
+
+// replace returns a copy of tree where the subtree rooted at i is replaced by subtree
+func replace(tree Tree, i int, subtree Tree) Tree {
+	end := tree.end(i)
+
+	child := make(Tree, 0, len(tree)-(end-i)+len(subtree))
+	child = append(child, tree[:i]...)
+	child = append(child, subtree...)
+
+	return append(child, tree[end:]...)
+}
+
+// SubtreeCrossover swaps a random subtree of a parent with a random subtree of the other parent. Crossover points are
+// function nodes with FunctionProbability, terminals otherwise (Koza suggests .9, since terminals are the majority of
+// the nodes and swapping them barely changes a program). A child deeper than MaxDepth, if greater than zero, is
+// replaced by a copy of its parent
+type SubtreeCrossover struct {
+	FunctionProbability float64
+	MaxDepth            int
+}
+
+func (s SubtreeCrossover) Cross(parents []Tree, r *rand.Rand) ([]Tree, error) {
+	if len(parents) != 2 {
+		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	mother, father := parents[0], parents[1]
+	i, j := point(mother, s.FunctionProbability, r), point(father, s.FunctionProbability, r)
+
+	children := []Tree{
+		replace(mother, i, father[j:father.end(j)]),
+		replace(father, j, mother[i:mother.end(i)]),
+	}
+
+	for k := range children {
+		if s.MaxDepth > 0 && children[k].Depth() > s.MaxDepth {
+			children[k] = parents[k].Clone()
+		}
+	}
+
+	return children, nil
+}
+
+func (s SubtreeCrossover) Children() int {
+	return 2
+}
+
+// PointMutation replaces every node, with the given Probability, with a random primitive of the same arity, so the
+// shape of the tree is preserved
+type PointMutation struct {
+	Set         *Set
+	Probability float64
+}
+
+func (p PointMutation) Mutate(tree Tree, r *rand.Rand) Tree {
+	for i, node := range tree {
+		if r.Float64() >= p.Probability {
+			continue
+		}
+
+		if node.Arity == 0 {
+			tree[i] = p.Set.terminal(r)
+		} else if function, ok := p.Set.function(node.Arity, r); ok {
+			tree[i] = function
+		}
+	}
+
+	return tree
+}
+
+// SubtreeMutation replaces, with the given Probability, a random subtree with a new one grown up to Depth. The
+// mutation is discarded if the tree becomes deeper than MaxDepth, if greater than zero
+type SubtreeMutation struct {
+	Set         *Set
+	Probability float64
+	Depth       int
+	MaxDepth    int
+}
+
+func (s SubtreeMutation) Mutate(tree Tree, r *rand.Rand) Tree {
+	if r.Float64() >= s.Probability {
+		return tree
+	}
+
+	mutated := replace(tree, r.Intn(len(tree)), s.Set.Grow(s.Depth, r))
+
+	if s.MaxDepth > 0 && mutated.Depth() > s.MaxDepth {
+		return tree
+	}
+
+	return mutated
+}
+
+// HoistMutation replaces, with the given Probability, the tree with one of its own subtrees rooted at a random
+// function node. Since the tree can only shrink, it counters bloat
+type HoistMutation struct {
+	Probability float64
+}
+
+func (h HoistMutation) Mutate(tree Tree, r *rand.Rand) Tree {
+	if r.Float64() >= h.Probability {
+		return tree
+	}
+
+	i := point(tree, 1, r)
+
+	return append(tree[:0], tree[i:tree.end(i)]...)
+}
@@ -0,0 +1,68 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package gp
+
+import (
+	"math"
+	"math/rand"
+)
+
+// The protected operators return a neutral value instead of NaN or infinities where the plain one is undefined, so
+// that every tree evaluates to a number
+var (
+	Add = Function{Name: "+", Arity: 2, Eval: func(args []float64) float64 { return args[0] + args[1] }}
+	Sub = Function{Name: "-", Arity: 2, Eval: func(args []float64) float64 { return args[0] - args[1] }}
+	Mul = Function{Name: "*", Arity: 2, Eval: func(args []float64) float64 { return args[0] * args[1] }}
+	// Div is the protected division, it returns 1 if the divisor is zero
+	Div = Function{Name: "/", Arity: 2, Eval: func(args []float64) float64 {
+		if args[1] == 0 {
+			return 1
+		}
+
+		return args[0] / args[1]
+	}}
+	Sin = Function{Name: "sin", Arity: 1, Eval: func(args []float64) float64 { return math.Sin(args[0]) }}
+	Cos = Function{Name: "cos", Arity: 1, Eval: func(args []float64) float64 { return math.Cos(args[0]) }}
+	// Log is the protected logarithm of the absolute value of its argument, it returns 0 for 0
+	Log = Function{Name: "log", Arity: 1, Eval: func(args []float64) float64 {
+		if args[0] == 0 {
+			return 0
+		}
+
+		return math.Log(math.Abs(args[0]))
+	}}
+)
+
+// Arithmetic returns the four protected arithmetic operators
+func Arithmetic() []Function {
+	return []Function{Add, Sub, Mul, Div}
+}
+
+// UniformConstant returns an ephemeral constant generator drawing from [min, max)
+func UniformConstant(min, max float64) func(r *rand.Rand) float64 {
+	return func(r *rand.Rand) float64 {
+		return min + r.Float64()*(max-min)
+	}
+}
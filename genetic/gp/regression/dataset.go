@@ -0,0 +1,130 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package regression
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+)
+
+// A Dataset is a table of samples: every row of Inputs holds the values of the variables named by Names, Targets the
+// value to be predicted for that row
+type Dataset struct {
+	Names   []string
+	Inputs  [][]float64
+	Targets []float64
+}
+
+// ReadCSV reads a dataset from a CSV with a header row naming the columns. The target is the column named target,
+// or the last one if target is empty, all the other columns are inputs
+func ReadCSV(r io.Reader, target string) (*Dataset, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("missing samples")
+	}
+
+	header := records[0]
+
+	column := len(header) - 1
+	if target != "" {
+		column = -1
+
+		for j, name := range header {
+			if name == target {
+				column = j
+			}
+		}
+
+		if column < 0 {
+			return nil, fmt.Errorf("missing target column %q", target)
+		}
+	}
+
+	if len(header) < 2 {
+		return nil, errors.New("missing input columns")
+	}
+
+	d := &Dataset{Names: append(append([]string(nil), header[:column]...), header[column+1:]...)}
+
+	for i, record := range records[1:] {
+		values := make([]float64, len(record))
+
+		for j, field := range record {
+			if values[j], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %v", i+2, header[j], err)
+			}
+		}
+
+		d.Targets = append(d.Targets, values[column])
+		d.Inputs = append(d.Inputs, append(values[:column:column], values[column+1:]...))
+	}
+
+	return d, nil
+}
+
+// LoadCSV reads a dataset from the CSV file at path, see ReadCSV
+func LoadCSV(path, target string) (*Dataset, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadCSV(file, target)
+}
+
+// Len returns the number of samples
+func (d *Dataset) Len() int {
+	return len(d.Targets)
+}
+
+// Split shuffles the samples and splits them in a training dataset and a held-out one, which gets the given fraction
+// of the samples
+func (d *Dataset) Split(fraction float64, r *rand.Rand) (train, test *Dataset) {
+	train, test = &Dataset{Names: d.Names}, &Dataset{Names: d.Names}
+	held := int(fraction * float64(d.Len()))
+
+	for n, i := range r.Perm(d.Len()) {
+		subset := train
+		if n < held {
+			subset = test
+		}
+
+		subset.Inputs = append(subset.Inputs, d.Inputs[i])
+		subset.Targets = append(subset.Targets, d.Targets[i])
+	}
+
+	return train, test
+}
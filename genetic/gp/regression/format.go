@@ -0,0 +1,283 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package regression
+
+import (
+	"fmt"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/marcopacini/go-genetic/genetic/gp"
+)
+
+// precedence of the formatted operators, an operand binding looser than its operator is parenthesized
+const (
+	additive = iota + 1
+	multiplicative
+	atom
+)
+
+// helpers are the Go sources of the functions of the gp package that have no counterpart in the standard library
+var helpers = map[string]string{
+	"/": `div := func(a, b float64) float64 {
+		if b == 0 {
+			return 1
+		}
+
+		return a / b
+	}`,
+	"log": `log := func(a float64) float64 {
+		if a == 0 {
+			return 0
+		}
+
+		return math.Log(math.Abs(a))
+	}`,
+}
+
+// Go returns the source of a Go function called name computing tree over set, it takes the variables of the set as
+// float64 arguments. The functions of the gp package are translated to operators, calls to the math package or
+// helpers declared within the function, any other function is called by name and has to be provided
+func Go(set *gp.Set, tree gp.Tree, name string) string {
+	g := golang{set: set, helpers: make(map[string]bool)}
+	body, _ := g.format(parse(tree))
+
+	var builder strings.Builder
+
+	parameters := make([]string, len(set.Variables))
+	for i, variable := range set.Variables {
+		parameters[i] = parameter(variable)
+	}
+
+	fmt.Fprintf(&builder, "func %s(%s float64) float64 {\n", identifier(name), strings.Join(parameters, ", "))
+
+	var used []string
+	for helper := range g.helpers {
+		used = append(used, helper)
+	}
+
+	sort.Strings(used)
+
+	for _, helper := range used {
+		fmt.Fprintf(&builder, "\t%s\n\n", helpers[helper])
+	}
+
+	fmt.Fprintf(&builder, "\treturn %s\n}\n", body)
+
+	return builder.String()
+}
+
+// identifier turns name into a valid Go identifier, a keyword gets a trailing underscore
+func identifier(name string) string {
+	id := []rune(name)
+
+	for i, c := range id {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			id[i] = '_'
+		}
+	}
+
+	if len(id) == 0 || unicode.IsDigit(id[0]) {
+		id = append([]rune{'_'}, id...)
+	}
+
+	if token.IsKeyword(string(id)) {
+		id = append(id, '_')
+	}
+
+	return string(id)
+}
+
+// reserved are the identifiers the body of the function refers to, parameters must not shadow them
+var reserved = map[string]bool{"div": true, "log": true, "math": true, "float64": true}
+
+// parameter turns the name of a variable into the identifier of its parameter
+func parameter(name string) string {
+	id := identifier(name)
+	if reserved[id] {
+		id += "_"
+	}
+
+	return id
+}
+
+type golang struct {
+	set     *gp.Set
+	helpers map[string]bool
+}
+
+// format returns the source of e and its precedence
+func (g golang) format(e *expression) (string, int) {
+	switch e.Kind {
+	case gp.VariableNode:
+		return parameter(g.set.Variables[e.Index]), atom
+	case gp.ConstantNode:
+		switch v := e.Value; {
+		case math.IsNaN(v):
+			return "math.NaN()", atom
+		case math.IsInf(v, 0):
+			return fmt.Sprintf("math.Inf(%d)", int(math.Copysign(1, v))), atom
+		}
+
+		// a float literal, so that the division of two constants is not an integer one
+		literal := strconv.FormatFloat(e.Value, 'g', -1, 64)
+		if !strings.ContainsAny(literal, ".e") {
+			literal += ".0"
+		}
+
+		return literal, atom
+	}
+
+	name, operands := signed(g.set.Functions[e.Index].Name, e.args)
+
+	args, precedences := make([]string, len(operands)), make([]int, len(operands))
+	for i, operand := range operands {
+		args[i], precedences[i] = g.format(operand)
+	}
+
+	switch {
+	case name == "+" && e.Arity == 2:
+		return infix(" + ", additive, true, args, precedences), additive
+	case name == "-" && e.Arity == 2:
+		return infix(" - ", additive, false, args, precedences), additive
+	case name == "*" && e.Arity == 2:
+		return infix(" * ", multiplicative, true, args, precedences), multiplicative
+	case name == "/" && e.Arity == 2:
+		if c, ok := e.args[1].constant(); ok && c != 0 {
+			return infix(" / ", multiplicative, false, args, precedences), multiplicative
+		}
+
+		g.helpers[name] = true
+
+		return fmt.Sprintf("div(%s)", strings.Join(args, ", ")), atom
+	case name == "sin" && e.Arity == 1:
+		return fmt.Sprintf("math.Sin(%s)", args[0]), atom
+	case name == "cos" && e.Arity == 1:
+		return fmt.Sprintf("math.Cos(%s)", args[0]), atom
+	case name == "log" && e.Arity == 1:
+		g.helpers[name] = true
+		return fmt.Sprintf("log(%s)", args[0]), atom
+	default:
+		return fmt.Sprintf("%s(%s)", identifier(name), strings.Join(args, ", ")), atom
+	}
+}
+
+// signed turns the addition or the subtraction of a negative constant into the opposite operation, x + -1 reads
+// x - 1
+func signed(name string, args []*expression) (string, []*expression) {
+	if c, ok := args[len(args)-1].constant(); ok && c < 0 && len(args) == 2 {
+		switch name {
+		case "+":
+			return "-", []*expression{args[0], constant(-c)}
+		case "-":
+			return "+", []*expression{args[0], constant(-c)}
+		}
+	}
+
+	return name, args
+}
+
+// infix formats a binary operator given its formatted operands. Unless the operator is associative, the right operand
+// is parenthesized also when it binds as tightly as the operator
+func infix(operator string, precedence int, associative bool, args []string, precedences []int) string {
+	left, right := args[0], args[1]
+
+	if precedences[0] < precedence {
+		left = "(" + left + ")"
+	}
+
+	if precedences[1] < precedence || (precedences[1] == precedence && !associative) {
+		right = "(" + right + ")"
+	}
+
+	return left + operator + right
+}
+
+// LaTeX returns tree over set as a LaTeX math formula
+func LaTeX(set *gp.Set, tree gp.Tree) string {
+	l := latex{set}
+	formula, _ := l.format(parse(tree))
+
+	return formula
+}
+
+type latex struct {
+	set *gp.Set
+}
+
+func (l latex) format(e *expression) (string, int) {
+	switch e.Kind {
+	case gp.VariableNode:
+		name := strings.ReplaceAll(l.set.Variables[e.Index], "_", `\_`)
+
+		if len([]rune(name)) > 1 {
+			return `\mathrm{` + name + `}`, atom
+		}
+
+		return name, atom
+	case gp.ConstantNode:
+		literal := strconv.FormatFloat(e.Value, 'g', -1, 64)
+
+		if i := strings.IndexByte(literal, 'e'); i >= 0 {
+			exponent, _ := strconv.Atoi(literal[i+1:])
+			literal = fmt.Sprintf(`%s \cdot 10^{%d}`, literal[:i], exponent)
+		}
+
+		if e.Value < 0 {
+			// a leading minus binds as a subtraction
+			return literal, additive
+		}
+
+		return literal, atom
+	}
+
+	name, operands := signed(l.set.Functions[e.Index].Name, e.args)
+
+	args, precedences := make([]string, len(operands)), make([]int, len(operands))
+	for i, operand := range operands {
+		args[i], precedences[i] = l.format(operand)
+	}
+
+	switch {
+	case name == "+" && e.Arity == 2:
+		return infix(" + ", additive, true, args, precedences), additive
+	case name == "-" && e.Arity == 2:
+		return infix(" - ", additive, false, args, precedences), additive
+	case name == "*" && e.Arity == 2:
+		return infix(` \cdot `, multiplicative, true, args, precedences), multiplicative
+	case name == "/" && e.Arity == 2:
+		return fmt.Sprintf(`\frac{%s}{%s}`, args[0], args[1]), atom
+	case name == "sin" || name == "cos":
+		return fmt.Sprintf(`\%s\left(%s\right)`, name, strings.Join(args, ", ")), atom
+	case name == "log" && e.Arity == 1:
+		return fmt.Sprintf(`\log\left|%s\right|`, args[0]), atom
+	default:
+		return fmt.Sprintf(`\operatorname{%s}\left(%s\right)`, name, strings.Join(args, ", ")), atom
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+// Package regression implements symbolic regression: it evolves, with the gp package, an expression fitting a
+// dataset, measures it on training and held-out samples and prints it, simplified, as Go or LaTeX source.
+package regression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/marcopacini/go-genetic/genetic"
+	"github.com/marcopacini/go-genetic/genetic/gp"
+)
+
+// Metrics measure how well an expression fits a dataset
+type Metrics struct {
+	MSE float64
+	// R2 is the coefficient of determination, 1 for a perfect fit and 0 for the mean of the targets
+	R2 float64
+}
+
+// Evaluate returns the metrics of tree over set on the samples of d, they are zero if d has no samples
+func Evaluate(set *gp.Set, tree gp.Tree, d *Dataset) Metrics {
+	if d.Len() == 0 {
+		return Metrics{}
+	}
+
+	mean := 0.
+	for _, target := range d.Targets {
+		mean += target
+	}
+
+	mean /= float64(d.Len())
+
+	residual, total := 0., 0.
+
+	for i, input := range d.Inputs {
+		residual += math.Pow(set.Eval(tree, input)-d.Targets[i], 2)
+		total += math.Pow(d.Targets[i]-mean, 2)
+	}
+
+	m := Metrics{MSE: residual / float64(d.Len()), R2: 1 - residual/total}
+
+	if total == 0 {
+		// constant targets: only a perfect fit explains them
+		m.R2 = 0
+		if residual == 0 {
+			m.R2 = 1
+		}
+	}
+
+	return m
+}
+
+// Regression configures a symbolic regression. The embedded configuration drives the engine, except Direction,
+// Init and Evaluator which are set by Run: the mean squared error on the training samples is minimized. Selection,
+// Crossover and Mutation default to a tournament of 7, subtree crossover and subtree mutation within DepthLimit
+type Regression struct {
+	genetic.Configuration[gp.Tree]
+	// Functions of the expressions, by default the protected arithmetic operators
+	Functions []gp.Function
+	// Constant, if set, generates the ephemeral random constants of the expressions
+	Constant func(r *rand.Rand) float64
+	// MinDepth and MaxDepth bound the depth of the initial expressions, by default 2 and 6
+	MinDepth, MaxDepth int
+	// DepthLimit is the maximum depth of the expressions bred by the default operators, by default 17
+	DepthLimit int
+	// Parsimony, if greater than zero, is the penalty of every node of an expression (see gp.Parsimony)
+	Parsimony float64
+}
+
+// Report is the outcome of a symbolic regression
+type Report struct {
+	// Set declares the primitives of the expressions, its variables are the inputs of the dataset
+	Set *gp.Set
+	// Expression is the simplified best expression
+	Expression gp.Tree
+	// Train and Test are the metrics of Expression on the training and held-out samples
+	Train, Test Metrics
+	Result      genetic.Result[gp.Tree]
+}
+
+// Go returns the source of a Go function called name computing the expression, see Go
+func (r Report) Go(name string) string {
+	return Go(r.Set, r.Expression, name)
+}
+
+// LaTeX returns the expression as a LaTeX math formula
+func (r Report) LaTeX() string {
+	return LaTeX(r.Set, r.Expression)
+}
+
+// String returns the expression in prefix notation
+func (r Report) String() string {
+	return r.Set.Format(r.Expression)
+}
+
+// Run evolves an expression fitting train and measures it on train and test. test may be nil or have no samples, in
+// which case Report.Test is left empty, otherwise its inputs must be the ones of train
+func (g *Regression) Run(ctx context.Context, train, test *Dataset) (Report, error) {
+	if train.Len() == 0 {
+		return Report{}, errors.New("missing training samples")
+	}
+
+	if test != nil && len(test.Names) != len(train.Names) {
+		return Report{}, fmt.Errorf("invalid held-out samples: %d inputs, want %d", len(test.Names), len(train.Names))
+	}
+
+	if test != nil {
+		for i, name := range test.Names {
+			if name != train.Names[i] {
+				return Report{}, fmt.Errorf("invalid held-out samples: input %d is %s, want %s", i, name, train.Names[i])
+			}
+		}
+	}
+
+	set := &gp.Set{Functions: g.Functions, Variables: train.Names, Constant: g.Constant}
+	if set.Functions == nil {
+		set.Functions = gp.Arithmetic()
+	}
+
+	if err := set.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	minDepth, maxDepth, limit := g.MinDepth, g.MaxDepth, g.DepthLimit
+	if minDepth == 0 {
+		minDepth = 2
+	}
+
+	if maxDepth == 0 {
+		maxDepth = 6
+	}
+
+	if limit == 0 {
+		limit = 17
+	}
+
+	configuration := g.Configuration
+	configuration.Direction = genetic.Minimize
+	configuration.Init = gp.Init(set, minDepth, maxDepth)
+	configuration.Evaluator = set.Evaluator(train.Inputs, gp.MeanSquaredError(train.Targets))
+
+	if g.Parsimony > 0 {
+		configuration.Evaluator = gp.Parsimony(configuration.Evaluator, g.Parsimony, genetic.Minimize)
+	}
+
+	if configuration.Selection == nil {
+		configuration.Selection = genetic.TournamentSelection{Size: 7}
+	}
+
+	if configuration.Crossover == nil {
+		configuration.Crossover = gp.SubtreeCrossover{FunctionProbability: .9, MaxDepth: limit}
+	}
+
+	if configuration.Mutation == nil {
+		configuration.Mutation = gp.SubtreeMutation{Set: set, Probability: .1, Depth: 4, MaxDepth: limit}
+	}
+
+	engine := &genetic.Engine[gp.Tree]{Configuration: configuration}
+
+	result, err := engine.Run(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Set: set, Expression: Simplify(set, result.Best.Genome), Result: result}
+	report.Train = Evaluate(set, report.Expression, train)
+
+	if test != nil && test.Len() > 0 {
+		report.Test = Evaluate(set, report.Expression, test)
+	}
+
+	return report, nil
+}
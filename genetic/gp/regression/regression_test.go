@@ -0,0 +1,238 @@
+package regression
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/marcopacini/go-genetic/genetic"
+	"github.com/marcopacini/go-genetic/genetic/gp"
+)
+
+func TestReadCSV(t *testing.T) {
+	const data = "x,y,z\n1,2,3\n4,5,6\n"
+
+	d, err := ReadCSV(strings.NewReader(data), "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(d.Names, ",") != "x,z" || d.Targets[1] != 5 || d.Inputs[1][0] != 4 || d.Inputs[1][1] != 6 {
+		t.Errorf("ReadCSV() = %+v", d)
+	}
+
+	if d, err = ReadCSV(strings.NewReader(data), ""); err != nil || d.Targets[0] != 3 || len(d.Inputs[0]) != 2 {
+		t.Errorf("ReadCSV() = %+v, %v, want z as target", d, err)
+	}
+
+	invalid := []struct{ data, target string }{
+		{data, "w"},
+		{"x\n1\n", ""},
+		{"x,y\n", ""},
+		{"x,y\n1,a\n", ""},
+	}
+
+	for _, test := range invalid {
+		if _, err := ReadCSV(strings.NewReader(test.data), test.target); err == nil {
+			t.Errorf("ReadCSV(%q, %q) error = nil, want error", test.data, test.target)
+		}
+	}
+}
+
+func TestDataset_Split(t *testing.T) {
+	d := &Dataset{Names: []string{"x"}}
+	for i := 0; i < 10; i++ {
+		d.Inputs = append(d.Inputs, []float64{float64(i)})
+		d.Targets = append(d.Targets, float64(i))
+	}
+
+	train, test := d.Split(.3, rand.New(rand.NewSource(1)))
+
+	if train.Len() != 7 || test.Len() != 3 {
+		t.Errorf("Split() = %d, %d samples, want 7, 3", train.Len(), test.Len())
+	}
+
+	for _, subset := range []*Dataset{train, test} {
+		for i, input := range subset.Inputs {
+			if input[0] != subset.Targets[i] {
+				t.Errorf("Split() shuffled inputs and targets apart")
+			}
+		}
+	}
+}
+
+func newTestSet() *gp.Set {
+	return &gp.Set{
+		Functions: append(gp.Arithmetic(), gp.Sin, gp.Log),
+		Variables: []string{"x", "y"},
+		Constant:  gp.UniformConstant(-2, 2),
+	}
+}
+
+// tree builds a tree from a compact prefix notation: functions by name, variables x and y and integer constants
+func tree(set *gp.Set, expression string) gp.Tree {
+	var t gp.Tree
+
+	for _, token := range strings.Fields(expression) {
+		switch token {
+		case "x", "y":
+			t = append(t, gp.Node{Kind: gp.VariableNode, Index: int(token[0] - 'x')})
+			continue
+		}
+
+		for i, f := range set.Functions {
+			if f.Name == token {
+				t = append(t, gp.Node{Kind: gp.FunctionNode, Index: i, Arity: f.Arity})
+				break
+			}
+		}
+
+		if v, err := strconv.ParseFloat(token, 64); err == nil {
+			t = append(t, gp.Node{Kind: gp.ConstantNode, Value: v})
+		}
+	}
+
+	return t
+}
+
+func TestSimplify(t *testing.T) {
+	set := newTestSet()
+
+	tests := []struct {
+		tree, want string
+	}{
+		{"+ x 0", "x"},
+		{"* 1 + 0 x", "x"},
+		{"- x x", "0"},
+		{"/ sin y sin y", "1"},
+		{"* x - y y", "0"},
+		{"+ * 2 3 x", "(+ x 6)"},
+		{"+ + 1 x 2", "(+ x 3)"},
+		{"* * x 3 2", "(* 6 x)"},
+		{"+ x x", "(* 2 x)"},
+		{"/ 1 0", "1"},
+		{"- sin x / y 1", "(- (sin x) y)"},
+	}
+
+	for _, test := range tests {
+		if got := set.Format(Simplify(set, tree(set, test.tree))); got != test.want {
+			t.Errorf("Simplify(%s) = %s, want %s", test.tree, got, test.want)
+		}
+	}
+
+	// random expressions keep their value
+	r := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 500; n++ {
+		original := set.Grow(6, r)
+		simplified := Simplify(set, original)
+
+		if simplified.Size() > original.Size() {
+			t.Fatalf("Simplify(%s) = %s, a larger expression", set.Format(original), set.Format(simplified))
+		}
+
+		for k := 0; k < 5; k++ {
+			input := []float64{r.NormFloat64(), r.NormFloat64()}
+
+			if a, b := set.Eval(original, input), set.Eval(simplified, input); math.Abs(a-b) > 1e-9*math.Max(1, math.Abs(a)) {
+				t.Fatalf("Simplify(%s) = %s, %f != %f", set.Format(original), set.Format(simplified), a, b)
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	set := newTestSet()
+
+	tests := []struct {
+		tree, golang, latex string
+	}{
+		{"- x - y 2", "x - (y - 2.0)", "x - (y - 2)"},
+		{"* + x 1 y", "(x + 1.0) * y", `(x + 1) \cdot y`},
+		{"/ sin x 2", "math.Sin(x) / 2.0", `\frac{\sin\left(x\right)}{2}`},
+		{"+ -1 / x y", "-1.0 + div(x, y)", `-1 + \frac{x}{y}`},
+		{"* x -0.00001", "x * -1e-05", `x \cdot (-1 \cdot 10^{-5})`},
+		{"+ x -1", "x - 1.0", "x - 1"},
+		{"- * 2 x -0.5", "2.0 * x + 0.5", `2 \cdot x + 0.5`},
+		{"log * x y", "log(x * y)", `\log\left|x \cdot y\right|`},
+	}
+
+	for _, test := range tests {
+		tree := tree(set, test.tree)
+
+		if got := Go(set, tree, "f"); !strings.Contains(got, "return "+test.golang+"\n") {
+			t.Errorf("Go(%s) = %s, want to return %s", test.tree, got, test.golang)
+		}
+
+		if got := LaTeX(set, tree); got != test.latex {
+			t.Errorf("LaTeX(%s) = %s, want %s", test.tree, got, test.latex)
+		}
+	}
+
+	// parameters neither clash with keywords nor with the identifiers of the body
+	reserved := &gp.Set{Functions: gp.Arithmetic(), Variables: []string{"func", "div", "math"}}
+	if got := Go(reserved, tree(reserved, "/ x y"), "type"); !strings.HasPrefix(got, "func type_(func_, div_, math_ float64)") {
+		t.Errorf("Go() = %s, want renamed parameters", got)
+	}
+
+	want := "func f(x, y float64) float64 {\n\tdiv := func(a, b float64) float64 {"
+	if got := Go(set, tree(set, "/ x y"), "f"); !strings.HasPrefix(got, want) {
+		t.Errorf("Go() = %s, want the div helper", got)
+	}
+}
+
+// TestRegression_Run fits y = x^2 + 2x - 1 and checks the fit on held-out samples
+func TestRegression_Run(t *testing.T) {
+	d := &Dataset{Names: []string{"x"}}
+
+	for x := -2.; x <= 2; x += .1 {
+		d.Inputs = append(d.Inputs, []float64{x})
+		d.Targets = append(d.Targets, x*x+2*x-1)
+	}
+
+	train, test := d.Split(.25, rand.New(rand.NewSource(1)))
+
+	regression := &Regression{
+		Configuration: genetic.Configuration[gp.Tree]{
+			PopulationSize: 300,
+			MaxAge:         10,
+			Elitism:        .05,
+			Iterations:     100,
+			Terminator:     genetic.TargetFitness{Fitness: 1e-12},
+			Seed:           1,
+		},
+		Constant: gp.UniformConstant(-1, 1),
+		MaxDepth: 4,
+	}
+
+	report, err := regression.Run(context.Background(), train, test)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Train.R2 < .99 || report.Test.R2 < .99 {
+		t.Errorf("Run() = %s, R2 %f (train) %f (test), want .99", report, report.Train.R2, report.Test.R2)
+	}
+
+	if report.Expression.Size() > report.Result.Best.Genome.Size() {
+		t.Errorf("report.Expression = %s, larger than the best expression", report)
+	}
+
+	if _, err := regression.Run(context.Background(), &Dataset{}, nil); err == nil {
+		t.Errorf("Run() error = nil, want error for an empty dataset")
+	}
+
+	if _, err := regression.Run(context.Background(), train, &Dataset{Names: []string{"y"}}); err == nil {
+		t.Errorf("Run() error = nil, want error for held-out samples of other inputs")
+	}
+
+	regression.Iterations = 1
+	train, test = d.Split(0, rand.New(rand.NewSource(1)))
+
+	if report, err := regression.Run(context.Background(), train, test); err != nil || report.Test != (Metrics{}) {
+		t.Errorf("Run() = %+v, %v, want empty held-out metrics", report.Test, err)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package regression
+
+import (
+	"math"
+
+	"github.com/marcopacini/go-genetic/genetic/gp"
+)
+
+// expression is a tree in linked form, easier to rewrite than the prefix one
+type expression struct {
+	gp.Node
+	args []*expression
+}
+
+func parse(tree gp.Tree) *expression {
+	var next func() *expression
+
+	i := 0
+	next = func() *expression {
+		e := &expression{Node: tree[i]}
+		i++
+
+		for k := 0; k < e.Arity; k++ {
+			e.args = append(e.args, next())
+		}
+
+		return e
+	}
+
+	return next()
+}
+
+// tree appends the receiver, in prefix order, to tree
+func (e *expression) tree(tree gp.Tree) gp.Tree {
+	tree = append(tree, e.Node)
+
+	for _, arg := range e.args {
+		tree = arg.tree(tree)
+	}
+
+	return tree
+}
+
+func (e *expression) constant() (float64, bool) {
+	return e.Value, e.Kind == gp.ConstantNode
+}
+
+// is reports whether the receiver is the constant v
+func (e *expression) is(v float64) bool {
+	c, ok := e.constant()
+	return ok && c == v
+}
+
+func (e *expression) equal(other *expression) bool {
+	return e.tree(nil).Equal(other.tree(nil))
+}
+
+func constant(v float64) *expression {
+	return &expression{Node: gp.Node{Kind: gp.ConstantNode, Value: v}}
+}
+
+type simplifier struct {
+	set *gp.Set
+}
+
+func (s simplifier) name(e *expression) string {
+	if e.Kind != gp.FunctionNode {
+		return ""
+	}
+
+	return s.set.Functions[e.Index].Name
+}
+
+// function returns a node applying the function called name to args, if the set has it
+func (s simplifier) function(name string, args ...*expression) (*expression, bool) {
+	for i, f := range s.set.Functions {
+		if f.Name == name && f.Arity == len(args) {
+			return &expression{Node: gp.Node{Kind: gp.FunctionNode, Index: i, Arity: f.Arity}, args: args}, true
+		}
+	}
+
+	return nil, false
+}
+
+// Simplify returns an expression equivalent to tree over set, usually a smaller one. Subexpressions made of constants
+// only are folded, and the identities of the functions named +, -, * and / are applied, assuming they are the
+// arithmetic operators of the gp package (/ being protected, x / x is always 1)
+func Simplify(set *gp.Set, tree gp.Tree) gp.Tree {
+	return simplifier{set}.simplify(parse(tree)).tree(nil)
+}
+
+func (s simplifier) simplify(e *expression) *expression {
+	if e.Kind != gp.FunctionNode {
+		return e
+	}
+
+	folded := true
+	values := make([]float64, len(e.args))
+
+	for i := range e.args {
+		e.args[i] = s.simplify(e.args[i])
+
+		var ok bool
+		if values[i], ok = e.args[i].constant(); !ok {
+			folded = false
+		}
+	}
+
+	if folded {
+		if v := s.set.Functions[e.Index].Eval(values); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return constant(v)
+		}
+	}
+
+	if e.Arity != 2 {
+		return e
+	}
+
+	a, b := e.args[0], e.args[1]
+
+	switch s.name(e) {
+	case "+":
+		// keep the constant on the right, x + 1
+		if _, ok := a.constant(); ok {
+			a, b = b, a
+			e.args[0], e.args[1] = a, b
+		}
+
+		switch {
+		case b.is(0):
+			return a
+		case a.equal(b):
+			if twice, ok := s.function("*", constant(2), a); ok {
+				return s.simplify(twice)
+			}
+		}
+
+		// (x + c) + d = x + (c + d)
+		if d, ok := b.constant(); ok && s.name(a) == "+" {
+			if c, ok := a.args[1].constant(); ok {
+				a.args[1] = constant(c + d)
+				return s.simplify(a)
+			}
+		}
+	case "-":
+		switch {
+		case b.is(0):
+			return a
+		case a.equal(b):
+			return constant(0)
+		}
+	case "*":
+		// keep the constant on the left, 2 * x
+		if _, ok := b.constant(); ok {
+			a, b = b, a
+			e.args[0], e.args[1] = a, b
+		}
+
+		switch {
+		case a.is(0):
+			return constant(0)
+		case a.is(1):
+			return b
+		}
+
+		// c * (d * x) = (c * d) * x
+		if c, ok := a.constant(); ok && s.name(b) == "*" {
+			if d, ok := b.args[0].constant(); ok {
+				b.args[0] = constant(c * d)
+				return s.simplify(b)
+			}
+		}
+	case "/":
+		switch {
+		case b.is(1):
+			return a
+		case a.equal(b):
+			return constant(1)
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,247 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Lengths constrains the number of genes of variable-length chromosomes to [Min, Max], a zero Max means no upper
+// limit. Length-changing operators never produce chromosomes out of these bounds
+type Lengths struct {
+	Min, Max int
+}
+
+// Validate returns an error if the constraint cannot be satisfied
+func (l Lengths) Validate() error {
+	if l.Min < 0 || (l.Max > 0 && l.Max < l.Min) {
+		return fmt.Errorf("invalid lengths: %+v (min must not be negative nor greater than max)", l)
+	}
+
+	return nil
+}
+
+// allows reports whether a chromosome of n genes satisfies the constraint
+func (l Lengths) allows(n int) bool {
+	return n >= l.Min && (l.Max == 0 || n <= l.Max)
+}
+
+// RandomChromosome returns a chromosome whose length is drawn uniformly from lengths, whose Max must be set, and
+// whose genes of geneLength values are drawn uniformly using r
+func RandomChromosome(lengths Lengths, geneLength int, r *rand.Rand) Chromosome {
+	chromosome := Chromosome{make([]Gene, lengths.Min+r.Intn(lengths.Max-lengths.Min+1))}
+
+	for i := range chromosome.Genes {
+		chromosome.Genes[i] = NewGene(geneLength)
+		chromosome.Genes[i].Randomize(r)
+	}
+
+	return chromosome
+}
+
+// GeneInsertion inserts, with the given Probability, a random gene of GeneLength values at a random position
+type GeneInsertion struct {
+	Lengths
+	GeneLength  int
+	Probability float64
+}
+
+func (m GeneInsertion) Mutate(chromosome Chromosome, r *rand.Rand) Chromosome {
+	if r.Float64() >= m.Probability || !m.allows(len(chromosome.Genes)+1) {
+		return chromosome
+	}
+
+	gene := NewGene(m.GeneLength)
+	gene.Randomize(r)
+
+	return chromosome.insert(r.Intn(len(chromosome.Genes)+1), gene)
+}
+
+// GeneDeletion removes, with the given Probability, a random gene
+type GeneDeletion struct {
+	Lengths
+	Probability float64
+}
+
+func (m GeneDeletion) Mutate(chromosome Chromosome, r *rand.Rand) Chromosome {
+	if r.Float64() >= m.Probability || len(chromosome.Genes) == 0 || !m.allows(len(chromosome.Genes)-1) {
+		return chromosome
+	}
+
+	i := r.Intn(len(chromosome.Genes))
+	chromosome.Genes = append(chromosome.Genes[:i], chromosome.Genes[i+1:]...)
+
+	return chromosome
+}
+
+// GeneDuplication inserts, with the given Probability, a copy of a random gene right after it
+type GeneDuplication struct {
+	Lengths
+	Probability float64
+}
+
+func (m GeneDuplication) Mutate(chromosome Chromosome, r *rand.Rand) Chromosome {
+	if r.Float64() >= m.Probability || len(chromosome.Genes) == 0 || !m.allows(len(chromosome.Genes)+1) {
+		return chromosome
+	}
+
+	i := r.Intn(len(chromosome.Genes))
+
+	return chromosome.insert(i+1, chromosome.Genes[i].Clone())
+}
+
+// insert returns the receiver with gene inserted at index i
+func (c Chromosome) insert(i int, gene Gene) Chromosome {
+	c.Genes = append(c.Genes, Gene{})
+	copy(c.Genes[i+1:], c.Genes[i:])
+	c.Genes[i] = gene
+
+	return c
+}
+
+// spliced returns a chromosome made of clones of head and tail
+func spliced(head, tail []Gene) Chromosome {
+	chromosome := Chromosome{make([]Gene, 0, len(head)+len(tail))}
+
+	for _, genes := range [][]Gene{head, tail} {
+		for _, gene := range genes {
+			chromosome.Genes = append(chromosome.Genes, gene.Clone())
+		}
+	}
+
+	return chromosome
+}
+
+// CutAndSpliceCrossover cuts each parent at its own random point and swaps the tails, so children can be longer or
+// shorter than their parents. The cuts are drawn by Lengths.Cuts, so the children satisfy Lengths
+type CutAndSpliceCrossover struct {
+	Lengths
+}
+
+func (c CutAndSpliceCrossover) Cross(parents []Chromosome, r *rand.Rand) ([]Chromosome, error) {
+	if len(parents) != 2 {
+		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	mother, father := parents[0].Genes, parents[1].Genes
+
+	i, j, err := c.Cuts(len(mother), len(father), r)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Chromosome{
+		spliced(mother[:i], father[j:]),
+		spliced(father[:j], mother[i:]),
+	}, nil
+}
+
+// Cuts draws the cut points i and j of two sequences of m and f elements such that swapping their tails produces
+// sequences satisfying the receiver: i is drawn among the cuts that allow it, j among the ones that then satisfy it
+func (l Lengths) Cuts(m, f int, r *rand.Rand) (int, int, error) {
+	// cutting at i and j the children have f + d and m - d elements, where d = i - j must lie in [lo, hi]
+	lo, hi := l.Min-f, m-l.Min
+
+	if l.Max > 0 {
+		if m-l.Max > lo {
+			lo = m - l.Max
+		}
+
+		if l.Max-f < hi {
+			hi = l.Max - f
+		}
+	}
+
+	if lo > hi || lo > m || f+hi < 0 {
+		return 0, 0, fmt.Errorf("invalid parents length: %d, %d (no cut satisfies %+v)", m, f, l)
+	}
+
+	// j = i - d must lie in [0, f], so i in [lo, f + hi]
+	first, last := limit(0, lo, m), limit(0, f+hi, m)
+	i := first + r.Intn(last-first+1)
+
+	first, last = limit(0, i-hi, f), limit(0, i-lo, f)
+	j := first + r.Intn(last-first+1)
+
+	return i, j, nil
+}
+
+// limit returns value bounded to [lower, upper]
+func limit(lower, value, upper int) int {
+	if value < lower {
+		return lower
+	}
+
+	if value > upper {
+		return upper
+	}
+
+	return value
+}
+
+func (c CutAndSpliceCrossover) Children() int {
+	return 2
+}
+
+// HomologousCrossover cuts both parents at the same random point within the genes they share, so genes keep their
+// position as homologous chromosomes do, and swaps the tails. Each child has the length of one of the parents
+type HomologousCrossover struct{}
+
+func (HomologousCrossover) Cross(parents []Chromosome, r *rand.Rand) ([]Chromosome, error) {
+	if len(parents) != 2 {
+		return nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	mother, father := parents[0].Genes, parents[1].Genes
+
+	shared := len(mother)
+	if len(father) < shared {
+		shared = len(father)
+	}
+
+	cut := r.Intn(shared + 1)
+
+	return []Chromosome{
+		spliced(mother[:cut], father[cut:]),
+		spliced(father[:cut], mother[cut:]),
+	}, nil
+}
+
+func (HomologousCrossover) Children() int {
+	return 2
+}
+
+// PenalizeLength wraps evaluate so that every gene of a chromosome costs Penalty fitness, worsening it according to
+// direction. It exerts a pressure towards short chromosomes
+func PenalizeLength(evaluate func(Chromosome) float64, penalty float64, direction Direction) func(Chromosome) float64 {
+	if direction == Minimize {
+		penalty = -penalty
+	}
+
+	return func(chromosome Chromosome) float64 {
+		return evaluate(chromosome) - penalty*float64(len(chromosome.Genes))
+	}
+}
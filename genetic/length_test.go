@@ -0,0 +1,149 @@
+package genetic
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestLength_Mutate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	lengths := Lengths{Min: 2, Max: 6}
+
+	mutators := []Mutator[Chromosome]{
+		GeneInsertion{Lengths: lengths, GeneLength: 3, Probability: .5},
+		GeneDeletion{Lengths: lengths, Probability: .5},
+		GeneDuplication{Lengths: lengths, Probability: .5},
+	}
+
+	for _, mutator := range mutators {
+		chromosome := NewChromosome(4, 3)
+		changed := false
+
+		for i := 0; i < 100; i++ {
+			n := len(chromosome.Genes)
+			chromosome = mutator.Mutate(chromosome, r)
+
+			if !lengths.allows(len(chromosome.Genes)) {
+				t.Fatalf("%T: len(chromosome.Genes) = %d, out of %+v", mutator, len(chromosome.Genes), lengths)
+			}
+
+			changed = changed || n != len(chromosome.Genes)
+
+			for _, gene := range chromosome.Genes {
+				if len(gene.Sequence) != 3 {
+					t.Fatalf("%T: len(gene.Sequence) = %d, want 3", mutator, len(gene.Sequence))
+				}
+			}
+		}
+
+		if !changed {
+			t.Errorf("%T: the length of the chromosome never changed", mutator)
+		}
+	}
+}
+
+func TestLength_Cross(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	lengths := Lengths{Min: 1, Max: 8}
+
+	for i := 0; i < 100; i++ {
+		mother, father := RandomChromosome(lengths, 2, r), RandomChromosome(lengths, 2, r)
+		total := len(mother.Genes) + len(father.Genes)
+
+		children, err := CutAndSpliceCrossover{lengths}.Cross([]Chromosome{mother, father}, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := len(children[0].Genes) + len(children[1].Genes); n != total {
+			t.Fatalf("CutAndSpliceCrossover: children have %d genes, want %d", n, total)
+		}
+
+		for _, child := range children {
+			if !lengths.allows(len(child.Genes)) {
+				t.Fatalf("CutAndSpliceCrossover: len(child.Genes) = %d, out of %+v", len(child.Genes), lengths)
+			}
+		}
+
+		children, err = HomologousCrossover{}.Cross([]Chromosome{mother, father}, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(children[0].Genes) != len(father.Genes) || len(children[1].Genes) != len(mother.Genes) {
+			t.Fatalf("HomologousCrossover: children lengths = %d, %d, want %d, %d",
+				len(children[0].Genes), len(children[1].Genes), len(father.Genes), len(mother.Genes))
+		}
+	}
+
+	parents := []Chromosome{NewChromosome(2, 1), NewChromosome(2, 1)}
+
+	if _, err := (CutAndSpliceCrossover{Lengths{Min: 10}}).Cross(parents, r); err == nil {
+		t.Errorf("Cross() error = nil, want error for unsatisfiable lengths")
+	}
+
+	// the cuts are drawn in closed form, they must be found whenever a pair of cuts satisfies the constraint
+	for _, lengths := range []Lengths{{Min: 3, Max: 5}, {Min: 4}, {Max: 3}, {Min: 6, Max: 6}} {
+		for m := 0; m < 8; m++ {
+			for f := 0; f < 8; f++ {
+				satisfiable := false
+
+				for i := 0; i <= m; i++ {
+					for j := 0; j <= f; j++ {
+						satisfiable = satisfiable || (lengths.allows(i+f-j) && lengths.allows(j+m-i))
+					}
+				}
+
+				parents := []Chromosome{{make([]Gene, m)}, {make([]Gene, f)}}
+
+				children, err := CutAndSpliceCrossover{lengths}.Cross(parents, r)
+				if (err == nil) != satisfiable {
+					t.Fatalf("Cross(%d, %d genes) error = %v, want satisfiable %v for %+v", m, f, err, satisfiable, lengths)
+				}
+
+				for _, child := range children {
+					if !lengths.allows(len(child.Genes)) {
+						t.Fatalf("Cross(%d, %d genes): len(child.Genes) = %d, out of %+v", m, f, len(child.Genes), lengths)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestEngine_RunVariableLength evolves chromosomes towards a length of 5 genes, all of them close to one
+func TestEngine_RunVariableLength(t *testing.T) {
+	lengths := Lengths{Min: 1, Max: 20}
+
+	engine := newTestEngine()
+	engine.Crossover = CutAndSpliceCrossover{lengths}
+	engine.Mutation = Mutations[Chromosome]{
+		Gaussian{Probability: .1, Std: .1},
+		GeneInsertion{Lengths: lengths, GeneLength: 2, Probability: .1},
+		GeneDeletion{Lengths: lengths, Probability: .1},
+	}
+	engine.Init = func(e *Engine[Chromosome]) {
+		for i := range e.Population {
+			e.Population[i].Genome = RandomChromosome(lengths, 2, e.Rand())
+		}
+	}
+	engine.Evaluator = PenalizeLength(func(c Chromosome) float64 {
+		if len(c.Genes) > 5 {
+			return sum(Chromosome{c.Genes[:5]})
+		}
+
+		return sum(c)
+	}, .1, Maximize)
+	engine.Iterations = 200
+	engine.Seed = 1
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(result.Best.Genome.Genes); n != 5 {
+		t.Errorf("len(result.Best.Genome.Genes) = %d, want 5", n)
+	}
+}
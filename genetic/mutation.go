@@ -26,8 +26,21 @@ package genetic
 
 import "math/rand"
 
-type Mutator interface {
-	Mutate(*Gene)
+// A Mutator alters genome using r as source of randomness, it returns the mutated genome. Genome is a child
+// owned by the caller, so it can be mutated in place
+type Mutator[G any] interface {
+	Mutate(genome G, r *rand.Rand) G
+}
+
+// Mutations applies its mutators one after the other, as a gene-wise mutation followed by a structural one
+type Mutations[G any] []Mutator[G]
+
+func (m Mutations[G]) Mutate(genome G, r *rand.Rand) G {
+	for _, mutator := range m {
+		genome = mutator.Mutate(genome, r)
+	}
+
+	return genome
 }
 
 func bound(lower, value, upper float64) float64 {
@@ -46,24 +59,33 @@ type Uniform struct {
 	Probability float64
 }
 
-// Apply uniform mutation on gene
-func (u Uniform) Mutate(gene *Gene) {
-	for i := range gene.Sequence {
-		if rand.Float64() < u.Probability {
-			gene.Sequence[i] = rand.Float64()
-			// gene.Sequence[i] = bound(0., gene.Sequence[i]+(2.*rand.Float64()-1.)*u.Magnitude, 1.)
+// Apply uniform mutation on every gene of chromosome
+func (u Uniform) Mutate(chromosome Chromosome, r *rand.Rand) Chromosome {
+	for _, gene := range chromosome.Genes {
+		for i := range gene.Sequence {
+			if r.Float64() < u.Probability {
+				gene.Sequence[i] = r.Float64()
+				// gene.Sequence[i] = bound(0., gene.Sequence[i]+(2.*rand.Float64()-1.)*u.Magnitude, 1.)
+			}
 		}
 	}
+
+	return chromosome
 }
 
 type Gaussian struct {
 	Probability, Std, Mean float64
 }
 
-func (g Gaussian) Mutate(gene *Gene) {
-	for i := range gene.Sequence {
-		if rand.Float64() < g.Probability {
-			gene.Sequence[i] = bound(0., gene.Sequence[i]+(rand.NormFloat64()*g.Std+g.Mean), 1.)
+// Apply gaussian mutation on every gene of chromosome
+func (g Gaussian) Mutate(chromosome Chromosome, r *rand.Rand) Chromosome {
+	for _, gene := range chromosome.Genes {
+		for i := range gene.Sequence {
+			if r.Float64() < g.Probability {
+				gene.Sequence[i] = bound(0., gene.Sequence[i]+(r.NormFloat64()*g.Std+g.Mean), 1.)
+			}
 		}
 	}
+
+	return chromosome
 }
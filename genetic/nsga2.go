@@ -0,0 +1,195 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Dominates reports whether objectives a Pareto-dominate objectives b, that is a is not worse than b in any
+// objective and it is strictly better in at least one
+func (d Direction) Dominates(a, b []float64) bool {
+	better := false
+
+	for i := range a {
+		if d.Better(b[i], a[i]) {
+			return false
+		}
+
+		if d.Better(a[i], b[i]) {
+			better = true
+		}
+	}
+
+	return better
+}
+
+// fronts performs the fast non-dominated sort of population, it sets the Rank of every individual and returns
+// the indexes of the individuals of each front
+// https://doi.org/10.1109/4235.996017
+func fronts[G any](population []Phenotype[G], direction Direction) [][]int {
+	dominated := make([][]int, len(population))
+	counter := make([]int, len(population))
+
+	for i := range population {
+		for j := i + 1; j < len(population); j++ {
+			switch {
+			case direction.Dominates(population[i].Objectives, population[j].Objectives):
+				dominated[i] = append(dominated[i], j)
+				counter[j]++
+			case direction.Dominates(population[j].Objectives, population[i].Objectives):
+				dominated[j] = append(dominated[j], i)
+				counter[i]++
+			}
+		}
+	}
+
+	var front []int
+
+	for i := range population {
+		if counter[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	var result [][]int
+
+	for rank := 0; len(front) > 0; rank++ {
+		var next []int
+
+		for _, i := range front {
+			population[i].Rank = rank
+
+			for _, j := range dominated[i] {
+				if counter[j]--; counter[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+
+		result = append(result, front)
+		front = next
+	}
+
+	return result
+}
+
+// crowd sets the crowding distance of the individuals of front, boundary individuals get an infinite distance
+func crowd[G any](population []Phenotype[G], front []int) {
+	for _, i := range front {
+		population[i].Crowding = 0.
+	}
+
+	if len(front) == 0 {
+		return
+	}
+
+	for m := range population[front[0]].Objectives {
+		sort.Slice(front, func(i, j int) bool {
+			return population[front[i]].Objectives[m] < population[front[j]].Objectives[m]
+		})
+
+		first, last := population[front[0]].Objectives[m], population[front[len(front)-1]].Objectives[m]
+
+		population[front[0]].Crowding = math.Inf(1)
+		population[front[len(front)-1]].Crowding = math.Inf(1)
+
+		if first == last {
+			continue
+		}
+
+		for k := 1; k < len(front)-1; k++ {
+			distance := population[front[k+1]].Objectives[m] - population[front[k-1]].Objectives[m]
+			population[front[k]].Crowding += distance / (last - first)
+		}
+	}
+}
+
+// crowdedLess is the crowded-comparison operator: lower rank first, then larger crowding distance
+func crowdedLess(a, b Score) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+
+	return a.Crowding > b.Crowding
+}
+
+// survive returns the best n individuals of population according to the crowded-comparison operator, sorted from
+// the best to the worst
+func (e *Engine[G]) survive(population []Phenotype[G], n int) []Phenotype[G] {
+	for _, front := range fronts(population, e.Direction) {
+		crowd(population, front)
+	}
+
+	sort.SliceStable(population, func(i, j int) bool {
+		return crowdedLess(population[i].Score, population[j].Score)
+	})
+
+	return population[:n:n]
+}
+
+// ParetoFront returns the non-dominated individuals of the population in multi-objective optimisation
+func (e *Engine[G]) ParetoFront() []Phenotype[G] {
+	var front []Phenotype[G]
+
+	for _, phenotype := range e.Population {
+		if phenotype.Rank == 0 {
+			front = append(front, phenotype)
+		}
+	}
+
+	return front
+}
+
+// CrowdedTournamentSelection is the binary tournament of NSGA-II generalised to Size contenders, the winner is the
+// one with the lowest Rank or, within the same front, with the largest Crowding distance
+type CrowdedTournamentSelection struct {
+	Size int
+}
+
+func (c CrowdedTournamentSelection) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
+	if n > len(population) {
+		return nil, fmt.Errorf("invalid selection size: %v > %v (population size)", n, len(population))
+	}
+
+	selection := make([]int, n)
+
+	for i := range selection {
+		best := r.Intn(len(population))
+
+		for j := 1; j < c.Size; j++ {
+			if k := r.Intn(len(population)); crowdedLess(population[k], population[best]) {
+				best = k
+			}
+		}
+
+		selection[i] = best
+	}
+
+	return selection, nil
+}
@@ -0,0 +1,102 @@
+package genetic
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestDirection_Dominates(t *testing.T) {
+	tests := []struct {
+		direction Direction
+		a, b      []float64
+		want      bool
+	}{
+		{Maximize, []float64{2, 2}, []float64{1, 2}, true},
+		{Maximize, []float64{2, 2}, []float64{2, 2}, false},
+		{Maximize, []float64{2, 1}, []float64{1, 2}, false},
+		{Minimize, []float64{1, 2}, []float64{2, 2}, true},
+		{Minimize, []float64{2, 2}, []float64{1, 2}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.direction.Dominates(test.a, test.b); got != test.want {
+			t.Errorf("%v.Dominates(%v, %v) = %v, want %v", test.direction, test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestFronts(t *testing.T) {
+	population := []Phenotype[Chromosome]{
+		{Score: Score{Objectives: []float64{1, 5}}},
+		{Score: Score{Objectives: []float64{2, 2}}},
+		{Score: Score{Objectives: []float64{3, 3}}},
+		{Score: Score{Objectives: []float64{5, 1}}},
+		{Score: Score{Objectives: []float64{4, 4}}},
+	}
+
+	fronts(population, Minimize)
+
+	for i, want := range []int{0, 0, 1, 0, 2} {
+		if population[i].Rank != want {
+			t.Errorf("population[%d].Rank = %d, want %d", i, population[i].Rank, want)
+		}
+	}
+
+	crowd(population, []int{0, 1, 3})
+
+	if !math.IsInf(population[0].Crowding, 1) || !math.IsInf(population[3].Crowding, 1) {
+		t.Errorf("boundary crowding = %f, %f, want +Inf", population[0].Crowding, population[3].Crowding)
+	}
+
+	if population[1].Crowding != 2 {
+		t.Errorf("population[1].Crowding = %f, want 2", population[1].Crowding)
+	}
+}
+
+func TestEngine_RunMultiObjective(t *testing.T) {
+	engine := newTestEngine()
+	engine.Direction = Minimize
+	engine.Selection = CrowdedTournamentSelection{Size: 2}
+	engine.Evaluator = nil
+	engine.MultiEvaluator = func(ctx context.Context, c Chromosome) ([]float64, error) {
+		// the closer to zero the first gene is, the farther from zero the others are
+		x := c.Genes[0].Sequence[0]
+		return []float64{x, 1 - x + sum(c) - x}, nil
+	}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Front) == 0 {
+		t.Fatalf("len(result.Front) = 0, want > 0")
+	}
+
+	for _, a := range result.Front {
+		for _, b := range result.Front {
+			if Minimize.Dominates(a.Objectives, b.Objectives) {
+				t.Errorf("%v dominates %v within the Pareto front", a.Objectives, b.Objectives)
+			}
+		}
+	}
+}
+
+func TestEngine_RunMultiObjectiveSize(t *testing.T) {
+	for _, size := range []func(c Chromosome) int{
+		func(c Chromosome) int { return 1 + int(2*c.Genes[0].Sequence[0]) },
+		func(c Chromosome) int { return 0 },
+	} {
+		engine := newTestEngine()
+		engine.Selection = CrowdedTournamentSelection{Size: 2}
+		engine.Evaluator = nil
+		engine.MultiEvaluator = func(ctx context.Context, c Chromosome) ([]float64, error) {
+			return make([]float64, size(c)), nil
+		}
+
+		if _, err := engine.Run(context.Background()); err == nil {
+			t.Errorf("Run() error = nil, want error for objective vectors of different sizes")
+		}
+	}
+}
@@ -0,0 +1,443 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+// A Permutation is an ordering of the integers in [0, n), as the visiting order of a tour or the processing order
+// of a schedule. Its operators always produce valid permutations
+type Permutation []int
+
+// NewPermutation returns a random permutation of length n drawn using r
+func NewPermutation(n int, r *rand.Rand) Permutation {
+	if n < 0 {
+		panic(fmt.Sprintf("invalid argument: n = %d (must not be negative)", n))
+	}
+
+	return Permutation(r.Perm(n))
+}
+
+// Clone the receiver permutation
+func (p Permutation) Clone() Permutation {
+	return append(Permutation(nil), p...)
+}
+
+// Values returns the elements of the receiver
+func (p Permutation) Values() []float64 {
+	values := make([]float64, len(p))
+
+	for i, v := range p {
+		values[i] = float64(v)
+	}
+
+	return values
+}
+
+// Hash returns the FNV-1a hash of the receiver elements
+func (p Permutation) Hash() uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, v := range p {
+		binary.LittleEndian.PutUint64(buffer, uint64(v))
+		hash.Write(buffer)
+	}
+
+	return hash.Sum64()
+}
+
+// Equal reports whether the receiver and other have the same elements in the same order
+func (p Permutation) Equal(other Permutation) bool {
+	if len(p) != len(other) {
+		return false
+	}
+
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Valid reports whether the receiver holds every integer in [0, len(p)) exactly once
+func (p Permutation) Valid() bool {
+	seen := make([]bool, len(p))
+
+	for _, v := range p {
+		if v < 0 || v >= len(p) || seen[v] {
+			return false
+		}
+
+		seen[v] = true
+	}
+
+	return true
+}
+
+// positions returns the inverse of the receiver: the index of every element
+func (p Permutation) positions() []int {
+	positions := make([]int, len(p))
+
+	for i, v := range p {
+		positions[v] = i
+	}
+
+	return positions
+}
+
+// segment draws the bounds [a, b) of a random segment of a sequence of length n
+func segment(n int, r *rand.Rand) (int, int) {
+	a, b := r.Intn(n+1), r.Intn(n+1)
+
+	if a > b {
+		a, b = b, a
+	}
+
+	return a, b
+}
+
+// couple checks that parents are two permutations of the same length
+func couple(parents []Permutation) (Permutation, Permutation, error) {
+	if len(parents) != 2 {
+		return nil, nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	if len(parents[0]) != len(parents[1]) {
+		return nil, nil, fmt.Errorf("invalid parents length: %d != %d", len(parents[0]), len(parents[1]))
+	}
+
+	return parents[0], parents[1], nil
+}
+
+// PartiallyMappedCrossover (PMX) copies a random segment of a parent and fills the rest with the elements of the
+// other parent, resolving the conflicts through the mapping defined by the segment
+// https://en.wikipedia.org/wiki/Crossover_(genetic_algorithm)#Partially_mapped_crossover_(PMX)
+type PartiallyMappedCrossover struct{}
+
+func (PartiallyMappedCrossover) Cross(parents []Permutation, r *rand.Rand) ([]Permutation, error) {
+	mother, father, err := couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := segment(len(mother), r)
+
+	return []Permutation{pmx(mother, father, a, b), pmx(father, mother, a, b)}, nil
+}
+
+func (PartiallyMappedCrossover) Children() int {
+	return 2
+}
+
+// pmx returns the child with the segment [a, b) of donor and the rest of receiver
+func pmx(donor, receiver Permutation, a, b int) Permutation {
+	child := receiver.Clone()
+	positions := receiver.positions()
+
+	copy(child[a:b], donor[a:b])
+
+	inside := make([]bool, len(donor))
+
+	for _, v := range donor[a:b] {
+		inside[v] = true
+	}
+
+	for i := a; i < b; i++ {
+		v := receiver[i]
+		if inside[v] {
+			continue
+		}
+
+		// follow the mapping until a position out of the segment
+		j := i
+		for j >= a && j < b {
+			j = positions[donor[j]]
+		}
+
+		child[j] = v
+	}
+
+	return child
+}
+
+// OrderCrossover (OX) copies a random segment of a parent and fills the rest with the missing elements in the order
+// they appear in the other parent, starting after the segment
+// https://en.wikipedia.org/wiki/Crossover_(genetic_algorithm)#Order_crossover_(OX1)
+type OrderCrossover struct{}
+
+func (OrderCrossover) Cross(parents []Permutation, r *rand.Rand) ([]Permutation, error) {
+	mother, father, err := couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	a, b := segment(len(mother), r)
+
+	return []Permutation{ox(mother, father, a, b), ox(father, mother, a, b)}, nil
+}
+
+func (OrderCrossover) Children() int {
+	return 2
+}
+
+// ox returns the child with the segment [a, b) of donor and the rest of receiver in order
+func ox(donor, receiver Permutation, a, b int) Permutation {
+	n := len(donor)
+	child := make(Permutation, n)
+	used := make([]bool, n)
+
+	copy(child[a:b], donor[a:b])
+
+	for _, v := range donor[a:b] {
+		used[v] = true
+	}
+
+	j := b
+	for k := 0; k < n; k++ {
+		v := receiver[(b+k)%n]
+		if used[v] {
+			continue
+		}
+
+		child[j%n] = v
+		j++
+	}
+
+	return child
+}
+
+// CycleCrossover (CX) splits the positions into the cycles defined by the parents, children take the elements of
+// alternate cycles from alternate parents, so every element keeps the position it has in one of the parents
+// https://en.wikipedia.org/wiki/Crossover_(genetic_algorithm)#Cycle_crossover_(CX)
+type CycleCrossover struct{}
+
+func (CycleCrossover) Cross(parents []Permutation, r *rand.Rand) ([]Permutation, error) {
+	mother, father, err := couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Permutation{make(Permutation, len(mother)), make(Permutation, len(mother))}
+	positions := mother.positions()
+	visited := make([]bool, len(mother))
+	swap := false
+
+	for start := range mother {
+		if visited[start] {
+			continue
+		}
+
+		for i := start; !visited[i]; i = positions[father[i]] {
+			visited[i] = true
+
+			if swap {
+				children[0][i], children[1][i] = father[i], mother[i]
+			} else {
+				children[0][i], children[1][i] = mother[i], father[i]
+			}
+		}
+
+		swap = !swap
+	}
+
+	return children, nil
+}
+
+func (CycleCrossover) Children() int {
+	return 2
+}
+
+// EdgeRecombinationCrossover (ERX) builds children that preserve the adjacencies of the parents, tours being
+// circular: every step moves to the neighbour of the current element with the fewest neighbours left. Each child
+// starts from the first element of a different parent
+// https://en.wikipedia.org/wiki/Edge_recombination_operator
+type EdgeRecombinationCrossover struct{}
+
+func (EdgeRecombinationCrossover) Cross(parents []Permutation, r *rand.Rand) ([]Permutation, error) {
+	mother, father, err := couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mother) == 0 {
+		return []Permutation{{}, {}}, nil
+	}
+
+	return []Permutation{erx(mother, father, mother[0], r), erx(mother, father, father[0], r)}, nil
+}
+
+func (EdgeRecombinationCrossover) Children() int {
+	return 2
+}
+
+// erx returns a child of mother and father starting from element
+func erx(mother, father Permutation, element int, r *rand.Rand) Permutation {
+	n := len(mother)
+	neighbours := make([]map[int]bool, n)
+
+	for v := range neighbours {
+		neighbours[v] = make(map[int]bool, 4)
+	}
+
+	for _, parent := range []Permutation{mother, father} {
+		for i, v := range parent {
+			if n > 1 {
+				neighbours[v][parent[(i+1)%n]] = true
+				neighbours[v][parent[(i+n-1)%n]] = true
+			}
+		}
+	}
+
+	child := make(Permutation, 0, n)
+	used := make([]bool, n)
+
+	for {
+		child = append(child, element)
+		used[element] = true
+
+		if len(child) == n {
+			return child
+		}
+
+		for v := range neighbours[element] {
+			delete(neighbours[v], element)
+		}
+
+		// the neighbours are sorted so that ties are broken by r alone, not by the order of the map
+		candidates := make([]int, 0, len(neighbours[element]))
+
+		for v := range neighbours[element] {
+			candidates = append(candidates, v)
+		}
+
+		sort.Ints(candidates)
+
+		next, fewest, ties := -1, 0, 0
+
+		for _, v := range candidates {
+			switch {
+			case next < 0 || len(neighbours[v]) < fewest:
+				next, fewest, ties = v, len(neighbours[v]), 1
+			case len(neighbours[v]) == fewest:
+				if ties++; r.Intn(ties) == 0 {
+					next = v
+				}
+			}
+		}
+
+		if next < 0 {
+			// dead end, restart from a random unused element
+			unused := make([]int, 0, n-len(child))
+
+			for v := range used {
+				if !used[v] {
+					unused = append(unused, v)
+				}
+			}
+
+			next = unused[r.Intn(len(unused))]
+		}
+
+		element = next
+	}
+}
+
+// Swap exchanges two random elements with the given Probability
+type Swap struct {
+	Probability float64
+}
+
+func (s Swap) Mutate(p Permutation, r *rand.Rand) Permutation {
+	if len(p) > 1 && r.Float64() < s.Probability {
+		i, j := r.Intn(len(p)), r.Intn(len(p))
+		p[i], p[j] = p[j], p[i]
+	}
+
+	return p
+}
+
+// Insertion moves a random element to a random position with the given Probability
+type Insertion struct {
+	Probability float64
+}
+
+func (s Insertion) Mutate(p Permutation, r *rand.Rand) Permutation {
+	if len(p) > 1 && r.Float64() < s.Probability {
+		i, j := r.Intn(len(p)), r.Intn(len(p))
+		v := p[i]
+
+		if i < j {
+			copy(p[i:j], p[i+1:j+1])
+		} else {
+			copy(p[j+1:i+1], p[j:i])
+		}
+
+		p[j] = v
+	}
+
+	return p
+}
+
+// Inversion reverses a random segment with the given Probability
+type Inversion struct {
+	Probability float64
+}
+
+func (s Inversion) Mutate(p Permutation, r *rand.Rand) Permutation {
+	if len(p) > 1 && r.Float64() < s.Probability {
+		a, b := segment(len(p), r)
+
+		for i, j := a, b-1; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+
+	return p
+}
+
+// Scramble shuffles a random segment with the given Probability
+type Scramble struct {
+	Probability float64
+}
+
+func (s Scramble) Mutate(p Permutation, r *rand.Rand) Permutation {
+	if len(p) > 1 && r.Float64() < s.Probability {
+		a, b := segment(len(p), r)
+
+		r.Shuffle(b-a, func(i, j int) {
+			p[a+i], p[a+j] = p[a+j], p[a+i]
+		})
+	}
+
+	return p
+}
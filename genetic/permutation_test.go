@@ -0,0 +1,128 @@
+package genetic
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPermutation_Cross(t *testing.T) {
+	crossovers := []Crossover[Permutation]{
+		PartiallyMappedCrossover{},
+		OrderCrossover{},
+		CycleCrossover{},
+		EdgeRecombinationCrossover{},
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	for _, crossover := range crossovers {
+		for n := 0; n < 20; n++ {
+			parents := []Permutation{NewPermutation(n, r), NewPermutation(n, r)}
+
+			children, err := crossover.Cross(parents, r)
+			if err != nil {
+				t.Fatalf("%T: Cross() error = %v", crossover, err)
+			}
+
+			if len(children) != crossover.Children() {
+				t.Fatalf("%T: len(children) = %d, want %d", crossover, len(children), crossover.Children())
+			}
+
+			for _, child := range children {
+				if len(child) != n || !child.Valid() {
+					t.Fatalf("%T: Cross(%v) = %v, not a permutation", crossover, parents, child)
+				}
+			}
+		}
+
+		if _, err := crossover.Cross([]Permutation{{0, 1}, {0}}, r); err == nil {
+			t.Errorf("%T: Cross() error = nil, want error for parents of different length", crossover)
+		}
+	}
+}
+
+func TestPmx(t *testing.T) {
+	donor := Permutation{8, 4, 7, 3, 6, 2, 5, 1, 9, 0}
+	receiver := Permutation{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	want := Permutation{0, 7, 4, 3, 6, 2, 5, 1, 8, 9}
+
+	if child := pmx(donor, receiver, 3, 8); !child.Equal(want) {
+		t.Errorf("pmx() = %v, want %v", child, want)
+	}
+}
+
+func TestOx(t *testing.T) {
+	donor := Permutation{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	receiver := Permutation{8, 2, 6, 7, 1, 5, 4, 0, 3}
+	want := Permutation{7, 1, 2, 3, 4, 5, 0, 8, 6}
+
+	if child := ox(donor, receiver, 2, 6); !child.Equal(want) {
+		t.Errorf("ox() = %v, want %v", child, want)
+	}
+}
+
+func TestPermutation_Mutate(t *testing.T) {
+	mutators := []Mutator[Permutation]{Swap{1}, Insertion{1}, Inversion{1}, Scramble{1}}
+
+	r := rand.New(rand.NewSource(1))
+
+	for _, mutator := range mutators {
+		for n := 0; n < 20; n++ {
+			if p := mutator.Mutate(NewPermutation(n, r), r); len(p) != n || !p.Valid() {
+				t.Fatalf("%T: Mutate() = %v, not a permutation", mutator, p)
+			}
+		}
+	}
+}
+
+// TestEngine_RunPermutation solves a travelling salesman problem whose cities lie on a circle, so the optimal tour
+// visits them in angular order
+func TestEngine_RunPermutation(t *testing.T) {
+	const cities = 12
+
+	distance := func(a, b int) float64 {
+		return 2 * math.Sin(math.Pi*math.Abs(float64(a-b))/cities)
+	}
+
+	optimum := cities * distance(0, 1)
+
+	for _, crossover := range []Crossover[Permutation]{OrderCrossover{}, EdgeRecombinationCrossover{}} {
+		engine := &Engine[Permutation]{Configuration: Configuration[Permutation]{
+			PopulationSize: 50,
+			MaxAge:         10,
+			Direction:      Minimize,
+			Selection:      TournamentSelection{Size: 3},
+			Crossover:      crossover,
+			Mutation:       Inversion{Probability: .3},
+			Elitism:        .1,
+			Iterations:     500,
+			Init: func(e *Engine[Permutation]) {
+				for i := range e.Population {
+					e.Population[i].Genome = NewPermutation(cities, e.Rand())
+				}
+			},
+			Evaluator: func(p Permutation) float64 {
+				length := 0.
+
+				for i := range p {
+					length += distance(p[i], p[(i+1)%len(p)])
+				}
+
+				return length
+			},
+			Terminator: TargetFitness{Fitness: optimum + 1e-9},
+			Seed:       1,
+		}}
+
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Reason != Terminated {
+			t.Errorf("%T: result.Best.Fitness = %f, want %f", crossover, result.Best.Fitness, optimum)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+// OneMax is the classic benchmark problem counting the bits set, the optimum is the string of all ones
+func OneMax(b Bits) float64 {
+	return float64(b.Count())
+}
+
+// Knapsack is the 0/1 knapsack benchmark problem: bit i tells whether item i, with the given weight and value, is
+// packed. Items must be as many as the bits
+type Knapsack struct {
+	Weights  []float64
+	Values   []float64
+	Capacity float64
+}
+
+// Evaluate returns the value packed in b, to be maximised. An overweight knapsack scores the excess weight as a
+// negative fitness, so any feasible solution is better than an unfeasible one and lighter unfeasible ones are better
+func (k Knapsack) Evaluate(b Bits) float64 {
+	weight, value := 0., 0.
+
+	for i := 0; i < b.Len(); i++ {
+		if b.Get(i) {
+			weight += k.Weights[i]
+			value += k.Values[i]
+		}
+	}
+
+	if weight > k.Capacity {
+		return k.Capacity - weight
+	}
+
+	return value
+}
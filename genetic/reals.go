@@ -0,0 +1,274 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+// A Bound limits a dimension to [Min, Max]. A positive Step restricts it further to the values Min + k*Step, a Step
+// of 1 with an integer Min makes it an integer dimension
+type Bound struct {
+	Min, Max, Step float64
+}
+
+// Bounds declares the bound of every dimension of a real-valued genome
+type Bounds []Bound
+
+// Validate returns an error describing the first invalid bound, if any
+func (b Bounds) Validate() error {
+	for i, bound := range b {
+		if !(bound.Min <= bound.Max) || bound.Step < 0 || math.IsInf(bound.Max-bound.Min, 0) {
+			return fmt.Errorf("invalid bound %d: %+v (min must not be greater than max, step must not be negative)",
+				i, bound)
+		}
+	}
+
+	return nil
+}
+
+// snap returns value clamped to the bound and moved to the closest step
+func (b Bound) snap(value float64) float64 {
+	value = bound(b.Min, value, b.Max)
+
+	if b.Step > 0 {
+		value = b.Min + math.Round((value-b.Min)/b.Step)*b.Step
+
+		if value > b.Max {
+			value -= b.Step
+		}
+	}
+
+	return value
+}
+
+// Random returns a genome whose values are drawn uniformly within the bounds using r
+func (b Bounds) Random(r *rand.Rand) Reals {
+	reals := make(Reals, len(b))
+
+	for i, bound := range b {
+		reals[i] = bound.snap(bound.Min + r.Float64()*(bound.Max-bound.Min))
+	}
+
+	return reals
+}
+
+// Clamp moves every value of reals within its bound and to its closest step, in place
+func (b Bounds) Clamp(reals Reals) Reals {
+	b.check(len(reals))
+
+	for i := range reals {
+		reals[i] = b[i].snap(reals[i])
+	}
+
+	return reals
+}
+
+// Scale maps values in [0, 1], one per dimension, to the bounds. It decodes the genes of a Chromosome, or any other
+// normalised encoding, into the values they stand for
+func (b Bounds) Scale(values []float64) []float64 {
+	b.check(len(values))
+
+	scaled := make([]float64, len(values))
+
+	for i, value := range values {
+		scaled[i] = b[i].snap(b[i].Min + value*(b[i].Max-b[i].Min))
+	}
+
+	return scaled
+}
+
+// Decode returns the values of chromosome, one per gene value, scaled to the bounds
+func (b Bounds) Decode(chromosome Chromosome) []float64 {
+	return b.Scale(chromosome.Values())
+}
+
+func (b Bounds) check(n int) {
+	if n != len(b) {
+		panic(fmt.Sprintf("invalid argument: %d values for %d bounds", n, len(b)))
+	}
+}
+
+// Reals is a real-valued genome, each of its values is meant to lie within its own Bound
+type Reals []float64
+
+// Clone the receiver genome
+func (r Reals) Clone() Reals {
+	return append(Reals(nil), r...)
+}
+
+// Values returns the values of the receiver
+func (r Reals) Values() []float64 {
+	return r
+}
+
+// Hash returns the FNV-1a hash of the receiver values
+func (r Reals) Hash() uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, value := range r {
+		binary.LittleEndian.PutUint64(buffer, math.Float64bits(value))
+		hash.Write(buffer)
+	}
+
+	return hash.Sum64()
+}
+
+// Equal reports whether the receiver and other have the same values
+func (r Reals) Equal(other Reals) bool {
+	if len(r) != len(other) {
+		return false
+	}
+
+	for i := range r {
+		if r[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BoundedUniform redraws every value, with the given Probability, uniformly within its bound
+type BoundedUniform struct {
+	Bounds      Bounds
+	Probability float64
+}
+
+func (u BoundedUniform) Mutate(reals Reals, r *rand.Rand) Reals {
+	u.Bounds.check(len(reals))
+
+	for i, bound := range u.Bounds {
+		if r.Float64() < u.Probability {
+			reals[i] = bound.snap(bound.Min + r.Float64()*(bound.Max-bound.Min))
+		}
+	}
+
+	return reals
+}
+
+// BoundedGaussian adds, with the given Probability, a normal perturbation to every value. Std is relative to the
+// width of the bound, so dimensions of different scale are perturbed alike. On a stepped dimension a perturbation
+// always moves the value by at least a step
+type BoundedGaussian struct {
+	Bounds      Bounds
+	Probability float64
+	Std         float64
+}
+
+func (g BoundedGaussian) Mutate(reals Reals, r *rand.Rand) Reals {
+	g.Bounds.check(len(reals))
+
+	for i, bound := range g.Bounds {
+		if r.Float64() < g.Probability {
+			delta := r.NormFloat64() * g.Std * (bound.Max - bound.Min)
+
+			if bound.Step > 0 && math.Abs(delta) < bound.Step {
+				delta = math.Copysign(bound.Step, delta)
+			}
+
+			reals[i] = bound.snap(reals[i] + delta)
+		}
+	}
+
+	return reals
+}
+
+// couple checks that parents are two genomes as long as the bounds
+func (b Bounds) couple(parents []Reals) (Reals, Reals, error) {
+	if len(parents) != 2 {
+		return nil, nil, fmt.Errorf("invalid parents number: %v != 2", len(parents))
+	}
+
+	if len(parents[0]) != len(b) || len(parents[1]) != len(b) {
+		return nil, nil, fmt.Errorf("invalid parents length: %d, %d (must be %d)", len(parents[0]), len(parents[1]), len(b))
+	}
+
+	return parents[0], parents[1], nil
+}
+
+// BlendCrossover (BLX-α) draws every value of the children uniformly from the interval spanned by the parents,
+// extended on both sides by Alpha times its width, and clamped to the bound
+type BlendCrossover struct {
+	Bounds Bounds
+	Alpha  float64
+}
+
+func (c BlendCrossover) Cross(parents []Reals, r *rand.Rand) ([]Reals, error) {
+	mother, father, err := c.Bounds.couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Reals{make(Reals, len(mother)), make(Reals, len(mother))}
+
+	for i, bound := range c.Bounds {
+		lower, upper := math.Min(mother[i], father[i]), math.Max(mother[i], father[i])
+		extension := c.Alpha * (upper - lower)
+
+		for _, child := range children {
+			child[i] = bound.snap(lower - extension + r.Float64()*(upper-lower+2*extension))
+		}
+	}
+
+	return children, nil
+}
+
+func (c BlendCrossover) Children() int {
+	return 2
+}
+
+// ArithmeticCrossover returns two complementary weighted averages of the parents, with a weight drawn uniformly for
+// every value. Children of parents within the bounds lie within the bounds
+type ArithmeticCrossover struct {
+	Bounds Bounds
+}
+
+func (c ArithmeticCrossover) Cross(parents []Reals, r *rand.Rand) ([]Reals, error) {
+	mother, father, err := c.Bounds.couple(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Reals{make(Reals, len(mother)), make(Reals, len(mother))}
+
+	for i, bound := range c.Bounds {
+		w := r.Float64()
+
+		children[0][i] = bound.snap(w*mother[i] + (1-w)*father[i])
+		children[1][i] = bound.snap((1-w)*mother[i] + w*father[i])
+	}
+
+	return children, nil
+}
+
+func (c ArithmeticCrossover) Children() int {
+	return 2
+}
@@ -0,0 +1,122 @@
+package genetic
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var testBounds = Bounds{
+	{Min: -5, Max: 5},
+	{Min: 100, Max: 200, Step: 1},
+	{Min: 0, Max: 1, Step: .25},
+}
+
+// within reports whether every value of reals lies within its bound and on its step
+func within(bounds Bounds, reals Reals) bool {
+	for i, b := range bounds {
+		if reals[i] < b.Min || reals[i] > b.Max {
+			return false
+		}
+
+		if k := (reals[i] - b.Min) / b.Step; b.Step > 0 && math.Abs(k-math.Round(k)) > 1e-9 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestBounds(t *testing.T) {
+	if err := testBounds.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	if err := (Bounds{{Min: 1, Max: 0}}).Validate(); err == nil {
+		t.Errorf("Validate() = nil, want error for min greater than max")
+	}
+
+	if got := testBounds.Clamp(Reals{-10, 150.4, .8}); !got.Equal(Reals{-5, 150, .75}) {
+		t.Errorf("Clamp() = %v, want [-5 150 0.75]", got)
+	}
+
+	if got := testBounds.Scale([]float64{.5, 1, 0}); got[0] != 0 || got[1] != 200 || got[2] != 0 {
+		t.Errorf("Scale() = %v, want [0 200 0]", got)
+	}
+
+	chromosome := NewChromosome(3, 1)
+	chromosome.Genes[1].Sequence[0] = .5
+
+	if got := testBounds.Decode(chromosome); got[0] != -5 || got[1] != 150 || got[2] != 0 {
+		t.Errorf("Decode() = %v, want [-5 150 0]", got)
+	}
+}
+
+func TestReals_Operators(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	mutators := []Mutator[Reals]{
+		BoundedUniform{Bounds: testBounds, Probability: .5},
+		BoundedGaussian{Bounds: testBounds, Probability: .5, Std: .5},
+	}
+
+	crossovers := []Crossover[Reals]{
+		BlendCrossover{Bounds: testBounds, Alpha: .5},
+		ArithmeticCrossover{Bounds: testBounds},
+	}
+
+	for i := 0; i < 1000; i++ {
+		for _, mutator := range mutators {
+			if reals := mutator.Mutate(testBounds.Random(r), r); !within(testBounds, reals) {
+				t.Fatalf("%T: Mutate() = %v, out of bounds", mutator, reals)
+			}
+		}
+
+		for _, crossover := range crossovers {
+			children, err := crossover.Cross([]Reals{testBounds.Random(r), testBounds.Random(r)}, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, child := range children {
+				if !within(testBounds, child) {
+					t.Fatalf("%T: Cross() = %v, out of bounds", crossover, child)
+				}
+			}
+		}
+	}
+}
+
+func TestEngine_RunReals(t *testing.T) {
+	bounds := Bounds{{Min: -10, Max: 10}, {Min: -10, Max: 10}, {Min: 0, Max: 100, Step: 1}}
+
+	engine := &Engine[Reals]{Configuration: Configuration[Reals]{
+		PopulationSize: 50,
+		MaxAge:         10,
+		Direction:      Minimize,
+		Selection:      TournamentSelection{Size: 3},
+		Crossover:      BlendCrossover{Bounds: bounds, Alpha: .5},
+		Mutation:       BoundedGaussian{Bounds: bounds, Probability: .2, Std: .05},
+		Elitism:        .1,
+		Iterations:     200,
+		Init: func(e *Engine[Reals]) {
+			for i := range e.Population {
+				e.Population[i].Genome = bounds.Random(e.Rand())
+			}
+		},
+		Evaluator: func(x Reals) float64 {
+			return math.Pow(x[0]-3, 2) + math.Pow(x[1]+7, 2) + math.Abs(x[2]-42)
+		},
+		Seed: 1,
+	}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if best := result.Best.Genome; result.Best.Fitness > .01 || best[2] != 42 {
+		t.Errorf("result.Best = %v (%f), want close to [3 -7 42]", best, result.Best.Fitness)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Evaluator dispatches chromosomes to a pool of workers. Each request goes to the healthy worker with the fewest
+// requests in flight; a worker that fails is left out of the pool for Cooldown and the request is sent again to
+// another one, up to Retries times. It must be created with NewEvaluator
+type Evaluator struct {
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+	// Timeout of a single request, no timeout if zero
+	Timeout time.Duration
+	// Retries is the number of times a failed request is sent again
+	Retries int
+	// Cooldown is how long a failed worker is left out of the pool
+	Cooldown time.Duration
+	// BatchSize is the maximum number of chromosomes sent in a single request by EvaluateBatch
+	BatchSize int
+
+	mutex   sync.Mutex
+	workers []*worker
+	next    int
+}
+
+type worker struct {
+	endpoint string
+	inflight int
+	// down is the time until which the worker is left out of the pool
+	down time.Time
+}
+
+// NewEvaluator returns an evaluator dispatching chromosomes to the workers listening at endpoints
+func NewEvaluator(endpoints ...string) *Evaluator {
+	e := &Evaluator{
+		Timeout:   30 * time.Second,
+		Retries:   3,
+		Cooldown:  5 * time.Second,
+		BatchSize: 16,
+	}
+
+	for _, endpoint := range endpoints {
+		e.workers = append(e.workers, &worker{endpoint: endpoint})
+	}
+
+	return e
+}
+
+// Evaluate scores a single chromosome, it can be used as Configuration.ContextEvaluator
+func (e *Evaluator) Evaluate(ctx context.Context, chromosome genetic.Chromosome) (float64, error) {
+	fitness, err := e.send(ctx, []genetic.Chromosome{chromosome})
+	if err != nil {
+		return 0., err
+	}
+
+	return fitness[0], nil
+}
+
+// EvaluateBatch scores chromosomes sending them in requests of at most BatchSize chromosomes, as many requests in
+// flight as workers. It implements genetic.BatchEvaluator[genetic.Chromosome]
+func (e *Evaluator) EvaluateBatch(ctx context.Context, chromosomes []genetic.Chromosome) ([]float64, error) {
+	if len(e.workers) == 0 {
+		return nil, errors.New("no workers")
+	}
+
+	size := e.BatchSize
+	if size < 1 {
+		size = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fitness := make([]float64, len(chromosomes))
+	semaphore := make(chan struct{}, len(e.workers))
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var failure error
+
+	for start := 0; start < len(chromosomes); start += size {
+		end := start + size
+		if end > len(chromosomes) {
+			end = len(chromosomes)
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(start, end int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			result, err := e.send(ctx, chromosomes[start:end])
+			if err != nil {
+				once.Do(func() {
+					failure = err
+					cancel()
+				})
+				return
+			}
+
+			copy(fitness[start:end], result)
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	if failure != nil {
+		return nil, failure
+	}
+
+	return fitness, nil
+}
+
+// send posts chromosomes to a worker, retrying on another one if it fails
+func (e *Evaluator) send(ctx context.Context, chromosomes []genetic.Chromosome) ([]float64, error) {
+	if len(e.workers) == 0 {
+		return nil, errors.New("no workers")
+	}
+
+	body, err := json.Marshal(Request{chromosomes})
+	if err != nil {
+		return nil, err
+	}
+
+	var last error
+
+	for attempt := 0; attempt <= e.Retries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		w := e.acquire()
+
+		fitness, retry, err := e.post(ctx, w.endpoint, body, len(chromosomes))
+		// a request aborted by the caller, as EvaluateBatch does once another request has failed, is no fault of w
+		e.release(w, err != nil && retry && ctx.Err() == nil)
+
+		if err == nil {
+			return fitness, nil
+		}
+
+		if !retry || ctx.Err() != nil {
+			return nil, err
+		}
+
+		last = err
+	}
+
+	return nil, fmt.Errorf("%d attempts failed: %w", e.Retries+1, last)
+}
+
+// post sends a single request, it reports whether a failure is worth another attempt
+func (e *Evaluator) post(ctx context.Context, endpoint string, body []byte, n int) ([]float64, bool, error) {
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, true, fmt.Errorf("worker %s: %w", endpoint, err)
+	}
+
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("worker %s: %w", endpoint, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var failure errorResponse
+		_ = json.Unmarshal(content, &failure)
+
+		// client errors, evaluation errors included, would fail on any worker: only server errors are retried and
+		// put the worker in cooldown
+		retry := response.StatusCode >= http.StatusInternalServerError
+
+		return nil, retry, fmt.Errorf("worker %s: %s: %s", endpoint, response.Status, failure.Error)
+	}
+
+	var result Response
+
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, true, fmt.Errorf("worker %s: invalid response: %w", endpoint, err)
+	}
+
+	if len(result.Fitness) != n {
+		return nil, true, fmt.Errorf("worker %s: invalid response size: %d != %d", endpoint, len(result.Fitness), n)
+	}
+
+	return result.Fitness, false, nil
+}
+
+// acquire returns the healthy worker with the fewest requests in flight. If every worker is down, the one coming
+// back first is returned
+func (e *Evaluator) acquire() *worker {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	now := time.Now()
+
+	var best *worker
+
+	for i := range e.workers {
+		// start from a different worker every time, so that ties are broken round robin
+		w := e.workers[(e.next+i)%len(e.workers)]
+
+		switch {
+		case best == nil:
+			best = w
+		case now.Before(best.down) && w.down.Before(best.down):
+			best = w
+		case !now.Before(w.down) && !now.Before(best.down) && w.inflight < best.inflight:
+			best = w
+		case !now.Before(w.down) && now.Before(best.down):
+			best = w
+		}
+	}
+
+	e.next = (e.next + 1) % len(e.workers)
+	best.inflight++
+
+	return best
+}
+
+// release returns w to the pool, leaving it out for Cooldown if it has failed
+func (e *Evaluator) release(w *worker, failed bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	w.inflight--
+
+	if failed {
+		w.down = time.Now().Add(e.Cooldown)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+// Package remote evaluates chromosomes on a pool of worker processes reachable over HTTP. Workers expose the Handler
+// of this package, the engine uses an Evaluator either as ContextEvaluator or as BatchEvaluator.
+package remote
+
+import (
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Request is the body of a POST request to a worker
+type Request struct {
+	Chromosomes []genetic.Chromosome `json:"chromosomes"`
+}
+
+// Response is the body of a successful response of a worker, Fitness has the same order of Request.Chromosomes
+type Response struct {
+	Fitness []float64 `json:"fitness"`
+}
+
+// errorResponse is the body of a failed response of a worker
+type errorResponse struct {
+	Error string `json:"error"`
+}
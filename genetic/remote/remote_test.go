@@ -0,0 +1,241 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+func sum(ctx context.Context, c genetic.Chromosome) (float64, error) {
+	fitness := 0.
+
+	for _, gene := range c.Genes {
+		for _, value := range gene.Sequence {
+			fitness += value
+		}
+	}
+
+	return fitness, nil
+}
+
+func chromosomes(n int) []genetic.Chromosome {
+	chromosomes := make([]genetic.Chromosome, n)
+
+	for i := range chromosomes {
+		chromosomes[i] = genetic.NewChromosome(2, 2)
+		chromosomes[i].Genes[0].Sequence[0] = float64(i)
+	}
+
+	return chromosomes
+}
+
+func TestEvaluator_EvaluateBatch(t *testing.T) {
+	var requests int64
+
+	handler := Handler(sum)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	evaluator := NewEvaluator(server.URL, server.URL)
+	evaluator.BatchSize = 4
+
+	fitness, err := evaluator.EvaluateBatch(context.Background(), chromosomes(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range fitness {
+		if fitness[i] != float64(i) {
+			t.Errorf("fitness[%d] = %f, want %d", i, fitness[i], i)
+		}
+	}
+
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestEvaluator_Failover(t *testing.T) {
+	alive := httptest.NewServer(Handler(sum))
+	defer alive.Close()
+
+	dead := httptest.NewServer(Handler(sum))
+	dead.Close()
+
+	evaluator := NewEvaluator(dead.URL, alive.URL)
+	evaluator.BatchSize = 1
+
+	fitness, err := evaluator.EvaluateBatch(context.Background(), chromosomes(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range fitness {
+		if fitness[i] != float64(i) {
+			t.Errorf("fitness[%d] = %f, want %d", i, fitness[i], i)
+		}
+	}
+}
+
+func TestEvaluator_Timeout(t *testing.T) {
+	slow := httptest.NewServer(Handler(func(ctx context.Context, c genetic.Chromosome) (float64, error) {
+		select {
+		case <-ctx.Done():
+			return 0., ctx.Err()
+		case <-time.After(time.Second):
+			return 0., nil
+		}
+	}))
+	defer slow.Close()
+
+	evaluator := NewEvaluator(slow.URL)
+	evaluator.Timeout = 10 * time.Millisecond
+	evaluator.Retries = 1
+
+	if _, err := evaluator.Evaluate(context.Background(), chromosomes(1)[0]); err == nil {
+		t.Errorf("Evaluate() error = nil, want error")
+	}
+}
+
+func TestEvaluator_ClientError(t *testing.T) {
+	var requests int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		reply(w, http.StatusBadRequest, errorResponse{"invalid"})
+	}))
+	defer server.Close()
+
+	if _, err := NewEvaluator(server.URL).Evaluate(context.Background(), chromosomes(1)[0]); err == nil {
+		t.Errorf("Evaluate() error = nil, want error")
+	}
+
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+// TestEvaluator_EvaluationError checks that a genome failing deterministically, or scoring a fitness JSON cannot
+// encode, is neither retried nor puts the workers in cooldown
+func TestEvaluator_EvaluationError(t *testing.T) {
+	var requests int64
+
+	handler := Handler(func(ctx context.Context, c genetic.Chromosome) (float64, error) {
+		atomic.AddInt64(&requests, 1)
+
+		switch c.Genes[0].Sequence[0] {
+		case 1:
+			return 0, errors.New("invalid genome")
+		case 2:
+			return math.Inf(1), nil
+		}
+
+		return sum(ctx, c)
+	})
+
+	servers := []*httptest.Server{httptest.NewServer(handler), httptest.NewServer(handler)}
+	evaluator := NewEvaluator(servers[0].URL, servers[1].URL)
+
+	for _, server := range servers {
+		defer server.Close()
+	}
+
+	genomes := chromosomes(3)
+
+	for _, i := range []int{1, 2} {
+		atomic.StoreInt64(&requests, 0)
+
+		if _, err := evaluator.Evaluate(context.Background(), genomes[i]); err == nil {
+			t.Errorf("Evaluate(%d) error = nil, want error", i)
+		}
+
+		if requests != 1 {
+			t.Errorf("Evaluate(%d): requests = %d, want 1", i, requests)
+		}
+	}
+
+	for _, w := range evaluator.workers {
+		if !w.down.IsZero() {
+			t.Errorf("worker %s is down, want up", w.endpoint)
+		}
+	}
+
+	if _, err := evaluator.Evaluate(context.Background(), genomes[0]); err != nil {
+		t.Errorf("Evaluate(0) error = %v", err)
+	}
+}
+
+func TestEvaluator_EvaluateBatchFailure(t *testing.T) {
+	server := httptest.NewServer(Handler(func(ctx context.Context, c genetic.Chromosome) (float64, error) {
+		if c.Genes[0].Sequence[0] == 0 {
+			return 0., errors.New("failure")
+		}
+
+		// the other requests are still running when the first one fails
+		select {
+		case <-ctx.Done():
+			return 0., ctx.Err()
+		case <-time.After(time.Second):
+			return 1., nil
+		}
+	}))
+	defer server.Close()
+
+	evaluator := NewEvaluator(server.URL, server.URL, server.URL)
+	evaluator.BatchSize = 1
+
+	if _, err := evaluator.EvaluateBatch(context.Background(), chromosomes(3)); err == nil {
+		t.Fatal("EvaluateBatch() error = nil, want error")
+	}
+
+	// the requests aborted by the failure are not failures of their workers
+	for i, w := range evaluator.workers {
+		if !w.down.IsZero() {
+			t.Errorf("worker %d is down, want available", i)
+		}
+	}
+}
+
+func TestEvaluator_Engine(t *testing.T) {
+	server := httptest.NewServer(Handler(sum))
+	defer server.Close()
+
+	engine := genetic.Engine[genetic.Chromosome]{Configuration: genetic.Configuration[genetic.Chromosome]{
+		PopulationSize: 20,
+		MaxAge:         5,
+		Selection:      genetic.TournamentSelection{Size: 3},
+		Crossover:      genetic.UniformCrossover{},
+		Mutation:       genetic.Gaussian{Probability: .1, Std: .1},
+		Elitism:        .1,
+		Iterations:     5,
+		Init: func(e *genetic.Engine[genetic.Chromosome]) {
+			for i := range e.Population {
+				e.Population[i].Genome = genetic.NewChromosome(4, 2)
+
+				for j := range e.Population[i].Genome.Genes {
+					e.Population[i].Genome.Genes[j].Randomize(e.Rand())
+				}
+			}
+		},
+		BatchEvaluator: NewEvaluator(server.URL),
+	}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fitness, _ := sum(context.Background(), result.Best.Genome); fitness != result.Best.Fitness {
+		t.Errorf("result.Best.Fitness = %f, want %f", result.Best.Fitness, fitness)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Handler returns the worker side handler: it accepts POST requests carrying a Request and replies with a Response,
+// scoring the chromosomes with evaluate. The request context is passed to evaluate, so evaluations are aborted when
+// the client gives up. Evaluation errors, and fitness values JSON cannot encode (infinities and NaN), are replied with
+// 422 Unprocessable Entity: they would fail on any worker, so the client does not retry them
+func Handler(evaluate func(context.Context, genetic.Chromosome) (float64, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			reply(w, http.StatusMethodNotAllowed, errorResponse{fmt.Sprintf("invalid method: %s", r.Method)})
+			return
+		}
+
+		var request Request
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			reply(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+
+		response := Response{Fitness: make([]float64, len(request.Chromosomes))}
+
+		for i, chromosome := range request.Chromosomes {
+			fitness, err := evaluate(r.Context(), chromosome)
+			if err != nil {
+				reply(w, http.StatusUnprocessableEntity, errorResponse{fmt.Sprintf("evaluation: %v", err)})
+				return
+			}
+
+			response.Fitness[i] = fitness
+		}
+
+		reply(w, http.StatusOK, response)
+	})
+}
+
+// reply encodes body before writing the status, so that an encoding failure is replied as such
+func reply(w http.ResponseWriter, status int, body interface{}) {
+	var buffer bytes.Buffer
+
+	if err := json.NewEncoder(&buffer).Encode(body); err != nil {
+		status = http.StatusUnprocessableEntity
+		buffer.Reset()
+
+		_ = json.NewEncoder(&buffer).Encode(errorResponse{fmt.Sprintf("invalid response: %v", err)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, _ = w.Write(buffer.Bytes())
+}
@@ -26,25 +26,30 @@ package genetic
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
+	"sort"
 )
 
+// A Selection picks n individuals from population returning their indexes. Population holds the scores of the
+// individuals sorted from the best to the worst and it is shared among concurrent selections, so it must not be
+// modified
 type Selection interface {
-	Select([]Phenotype, int) ([]Chromosome, error)
+	Select(population []Score, n int, r *rand.Rand) ([]int, error)
 }
 
 type RandomSelection struct{}
 
-// Returns n Chromosomes selected randomly from population
-func (r RandomSelection) Select(population []Phenotype, n int) ([]Chromosome, error) {
+// Returns n individuals selected randomly from population
+func (s RandomSelection) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
 	if n > len(population) {
 		return nil, fmt.Errorf("invalid selection size: %v > %v (population size)", n, len(population))
 	}
 
-	selection := make([]Chromosome, n)
+	selection := make([]int, n)
 
 	for i := 0; i < n; i++ {
-		selection[i] = population[rand.Intn(len(population))].Chromosome
+		selection[i] = r.Intn(len(population))
 	}
 
 	return selection, nil
@@ -54,28 +59,24 @@ type ElitismSelection struct {
 	Size float64
 }
 
-// Returns n Chromosomes selected from the best individuals (elite). If the elite group size is lesser than
+// Returns n individuals selected from the best individuals (elite). If the elite group size is lesser than
 // the n, the elite group size ia automatically increased to n
-func (e ElitismSelection) Select(population []Phenotype, n int) ([]Chromosome, error) {
+func (e ElitismSelection) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
 	if n > len(population) {
 		return nil, fmt.Errorf("invalid selection size: %v > %v (population size)", n, len(population))
 	}
 
-	// sort.Sort(decreasing(population))
-
 	size := int(bound(float64(n), e.Size*float64(len(population)), float64(len(population))))
-	selection := make([]Chromosome, size)
+	selection := make([]int, size)
 
-	for i := range population[:size] {
-		selection[i] = population[rand.Intn(len(population))].Chromosome
+	// population is sorted, so the elite is its head
+	for i := range selection {
+		selection[i] = r.Intn(size)
 	}
 
-	func(slice []Chromosome) {
-		for i := range slice {
-			j := rand.Intn(len(slice))
-			slice[i], slice[j] = slice[j], slice[i]
-		}
-	}(selection)
+	r.Shuffle(len(selection), func(i, j int) {
+		selection[i], selection[j] = selection[j], selection[i]
+	})
 
 	return selection[:n], nil
 }
@@ -84,37 +85,192 @@ type TournamentSelection struct {
 	Size int
 }
 
-func (t TournamentSelection) Select(population []Phenotype, n int) ([]Chromosome, error) {
+// Returns n individuals, each one is the best of Size individuals drawn randomly from population. Since population
+// is sorted from the best to the worst, the winner is the one with the lowest index
+func (t TournamentSelection) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
 	if n > len(population) {
 		return nil, fmt.Errorf("invalid selection size: %v > %v (population size)", n, len(population))
 	}
 
-	// shuffle
-	shuffle := func() {
-		for i := range population {
-			j := rand.Intn(len(population))
-			population[i], population[j] = population[j], population[i]
+	tournament := func() int {
+		best := r.Intn(len(population))
+
+		for i := 1; i < t.Size; i++ {
+			if j := r.Intn(len(population)); j < best {
+				best = j
+			}
 		}
+
+		return best
 	}
 
-	tournament := func() Chromosome {
-		best := population[0]
+	selection := make([]int, n)
 
-		for _, phenotype := range population[:t.Size] {
-			if phenotype.Fitness > best.Fitness {
-				best = phenotype
-			}
+	for i := range selection {
+		selection[i] = tournament()
+	}
+
+	return selection, nil
+}
+
+// A Preparer is a selection that precomputes something about the population, as the prefix sums of the weights of
+// the fitness-proportionate selections. The engine calls Prepare whenever the population changes, once per generation
+// in generational evolution, and selects from the returned Selection until the next change
+type Preparer interface {
+	Prepare(population []Score, direction Direction) Selection
+}
+
+// weights returns the prefix sums of the selection weights of population, proportional to fitness. When maximizing
+// non-negative fitness the weight is the fitness itself, otherwise (negative fitness or minimization) the fitness is
+// windowed: the weight is the distance from the worst fitness, so the worst individual is never selected. If all the
+// weights are zero they are all set to one. Non-finite fitness is rejected, it would make the wheel a single slice
+func weights(population []Score, direction Direction) ([]float64, error) {
+	if len(population) == 0 {
+		return nil, nil
+	}
+
+	worst := population[0].Fitness
+
+	for _, score := range population {
+		if direction.Better(worst, score.Fitness) {
+			worst = score.Fitness
+		}
+	}
+
+	window := direction == Minimize || worst < 0
+
+	sums := make([]float64, len(population))
+	total := 0.
+
+	for i, score := range population {
+		weight := score.Fitness
+		if window {
+			weight = math.Abs(score.Fitness - worst)
 		}
 
-		return best.Chromosome
+		total += weight
+		sums[i] = total
 	}
 
-	var selection []Chromosome
+	if math.IsNaN(total) || math.IsInf(total, 0) {
+		return nil, fmt.Errorf("invalid fitness: total weight is %v (fitness must be finite)", total)
+	}
 
-	for i := 0; i < n; i++ {
-		shuffle()
-		selection = append(selection, tournament())
+	if total == 0 {
+		for i := range sums {
+			sums[i] = float64(i + 1)
+		}
 	}
 
+	return sums, nil
+}
+
+// wheel is a fitness-proportionate selection prepared for a population, sums being the prefix sums of its weights
+type wheel struct {
+	sums []float64
+	// universal tells stochastic universal sampling from roulette wheel selection
+	universal bool
+	// err is the error preparing the wheel, returned by Select
+	err error
+}
+
+// newWheel prepares a wheel for population
+func newWheel(population []Score, direction Direction, universal bool) wheel {
+	sums, err := weights(population, direction)
+	return wheel{sums: sums, universal: universal, err: err}
+}
+
+// spin returns the index of the individual whose slice of the wheel holds u, in O(log n)
+func (w wheel) spin(u float64) int {
+	i := sort.Search(len(w.sums), func(i int) bool {
+		return w.sums[i] > u
+	})
+
+	// u may be rounded up to the total
+	if i == len(w.sums) {
+		i--
+	}
+
+	return i
+}
+
+func (w wheel) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+
+	if n > len(population) {
+		return nil, fmt.Errorf("invalid selection size: %v > %v (population size)", n, len(population))
+	}
+
+	if len(population) != len(w.sums) {
+		return nil, fmt.Errorf("invalid population size: %d != %d (size of the prepared population)", len(population),
+			len(w.sums))
+	}
+
+	selection := make([]int, n)
+
+	if n == 0 {
+		return selection, nil
+	}
+
+	total := w.sums[len(w.sums)-1]
+
+	if !w.universal {
+		for i := range selection {
+			selection[i] = w.spin(r.Float64() * total)
+		}
+
+		return selection, nil
+	}
+
+	step := total / float64(n)
+	start := r.Float64() * step
+
+	for i := range selection {
+		selection[i] = w.spin(start + float64(i)*step)
+	}
+
+	// the pointers pick the individuals in order, shuffled they can be paired as parents
+	r.Shuffle(len(selection), func(i, j int) {
+		selection[i], selection[j] = selection[j], selection[i]
+	})
+
 	return selection, nil
 }
+
+// RouletteWheelSelection selects individuals with probability proportional to their fitness (see weights for
+// negative fitness and minimization). Sampling costs O(log n) once prepared, which costs O(n). Direction is the one
+// of the populations passed to Select, the engine prepares the wheel with its own Direction instead
+type RouletteWheelSelection struct {
+	Direction Direction
+}
+
+// Prepare returns the selection for population, see Preparer
+func (s RouletteWheelSelection) Prepare(population []Score, direction Direction) Selection {
+	return newWheel(population, direction, false)
+}
+
+// Returns n individuals, each one drawn independently with probability proportional to its fitness. The population
+// is prepared on every call, the engine prepares it once per generation
+func (s RouletteWheelSelection) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
+	return s.Prepare(population, s.Direction).Select(population, n, r)
+}
+
+// StochasticUniversalSampling selects individuals with probability proportional to their fitness as
+// RouletteWheelSelection does, but with a single spin of a wheel with n evenly spaced pointers: the number of copies
+// of an individual differs from its expected value by less than one. Direction is as in RouletteWheelSelection
+type StochasticUniversalSampling struct {
+	Direction Direction
+}
+
+// Prepare returns the selection for population, see Preparer
+func (s StochasticUniversalSampling) Prepare(population []Score, direction Direction) Selection {
+	return newWheel(population, direction, true)
+}
+
+// Returns n individuals, in random order, picked by the n pointers of the wheel. As for RouletteWheelSelection, the
+// population is prepared on every call
+func (s StochasticUniversalSampling) Select(population []Score, n int, r *rand.Rand) ([]int, error) {
+	return s.Prepare(population, s.Direction).Select(population, n, r)
+}
@@ -0,0 +1,217 @@
+package genetic
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction Direction
+		fitness   []float64
+		want      []float64
+	}{
+		{"maximize", Maximize, []float64{3, 2, 1, 0}, []float64{3, 5, 6, 6}},
+		{"negative", Maximize, []float64{1, -1, -2}, []float64{3, 4, 4}},
+		{"minimize", Minimize, []float64{-1, 0, 2}, []float64{3, 5, 5}},
+		{"unsorted", Minimize, []float64{0, 2, -1}, []float64{2, 2, 5}},
+		{"flat", Maximize, []float64{1, 1, 1}, []float64{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		population := make([]Score, len(test.fitness))
+		for i, fitness := range test.fitness {
+			population[i].Fitness = fitness
+		}
+
+		got, err := weights(population, test.direction)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: weights() = %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestWeights_NonFinite(t *testing.T) {
+	for _, fitness := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		population := []Score{{Fitness: 1}, {Fitness: fitness}}
+
+		for _, direction := range []Direction{Maximize, Minimize} {
+			if _, err := weights(population, direction); err == nil {
+				t.Errorf("weights(%v, %v) error = nil, want error", fitness, direction)
+			}
+		}
+	}
+
+	population := []Score{{Fitness: 1}, {Fitness: math.NaN()}}
+	r := rand.New(rand.NewSource(1))
+
+	if _, err := (RouletteWheelSelection{}).Select(population, 1, r); err == nil {
+		t.Errorf("Select() error = nil, want error")
+	}
+}
+
+func TestWheel_Select(t *testing.T) {
+	population := []Score{{Fitness: 1}, {Fitness: 2}, {Fitness: 3}}
+	r := rand.New(rand.NewSource(1))
+
+	for _, preparer := range []Preparer{RouletteWheelSelection{}, StochasticUniversalSampling{}} {
+		selection := preparer.Prepare(population, Minimize)
+
+		// the worst individual is never selected when minimizing
+		for k := 0; k < 100; k++ {
+			indexes, err := selection.Select(population, 3, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, i := range indexes {
+				if i == 2 {
+					t.Fatalf("%T: Select() = %v, selected the worst individual", preparer, indexes)
+				}
+			}
+		}
+
+		if _, err := selection.Select(population[:2], 1, r); err == nil {
+			t.Errorf("%T: Select() error = nil, want error for a population other than the prepared one", preparer)
+		}
+	}
+
+	// selecting directly, the direction is the one of the selection
+	for _, selection := range []Selection{RouletteWheelSelection{Minimize}, StochasticUniversalSampling{Minimize}} {
+		for k := 0; k < 100; k++ {
+			indexes, err := selection.Select(population, 3, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, i := range indexes {
+				if i == 2 {
+					t.Fatalf("%T: Select() = %v, selected the worst individual", selection, indexes)
+				}
+			}
+		}
+	}
+}
+
+func TestRouletteWheelSelection_Select(t *testing.T) {
+	population := []Score{{Fitness: 4}, {Fitness: 3}, {Fitness: 2}, {Fitness: 1}}
+	r := rand.New(rand.NewSource(1))
+
+	counts := make([]int, len(population))
+
+	for k := 0; k < 10000; k++ {
+		selection, err := RouletteWheelSelection{}.Select(population, 2, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, i := range selection {
+			counts[i]++
+		}
+	}
+
+	for i, count := range counts {
+		if want := 20000 * population[i].Fitness / 10; math.Abs(float64(count)-want) > .05*want {
+			t.Errorf("individual %d selected %d times, want about %.0f", i, count, want)
+		}
+	}
+
+	if _, err := (RouletteWheelSelection{}).Select(population, 5, r); err == nil {
+		t.Errorf("Select() error = nil, want error for a selection larger than population")
+	}
+}
+
+func TestStochasticUniversalSampling_Select(t *testing.T) {
+	population := []Score{{Fitness: 3}, {Fitness: 2}, {Fitness: 1}, {Fitness: 0}}
+	r := rand.New(rand.NewSource(1))
+
+	for k := 0; k < 1000; k++ {
+		selection, err := StochasticUniversalSampling{}.Select(population, 4, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts := make([]int, len(population))
+		for _, i := range selection {
+			counts[i]++
+		}
+
+		// every individual gets the floor or the ceiling of its expected copies
+		for i, count := range counts {
+			expected := 4 * population[i].Fitness / 6
+
+			if float64(count) < math.Floor(expected) || float64(count) > math.Ceil(expected) {
+				t.Fatalf("Select() = %v, individual %d expected %.2f times", selection, i, expected)
+			}
+		}
+	}
+
+	if _, err := (StochasticUniversalSampling{}).Select(population, 5, r); err == nil {
+		t.Errorf("Select() error = nil, want error for a selection larger than population")
+	}
+}
+
+func TestEngine_RunProportionate(t *testing.T) {
+	for _, selection := range []Selection{RouletteWheelSelection{}, StochasticUniversalSampling{}} {
+		for _, direction := range []Direction{Maximize, Minimize} {
+			engine := newTestEngine()
+			engine.Selection = selection
+			engine.Direction = direction
+			engine.Seed = 1
+
+			first := 0.
+
+			engine.Observer = func(i int, e *Engine[Chromosome]) {
+				if i == 0 {
+					first = e.Stats().Mean
+				}
+			}
+
+			if _, err := engine.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if mean := engine.Stats().Mean; engine.Better(first, mean) {
+				t.Errorf("%T, %v: mean fitness %f, worse than %f at the first generation", selection, direction, mean, first)
+			}
+		}
+	}
+}
+
+// preparations counts the populations prepared by the engine
+type preparations struct {
+	RouletteWheelSelection
+	n *int64
+}
+
+func (p preparations) Prepare(population []Score, direction Direction) Selection {
+	atomic.AddInt64(p.n, 1)
+	return p.RouletteWheelSelection.Prepare(population, direction)
+}
+
+func TestEngine_RunPrepare(t *testing.T) {
+	var n int64
+
+	engine := newTestEngine()
+	engine.Selection = preparations{n: &n}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != int64(result.Generations) {
+		t.Errorf("Prepare() called %d times, want once per generation (%d)", n, result.Generations)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// GenerationStats summarises the population at the end of a generation
+type GenerationStats struct {
+	// Generation is the number of generations completed, zero for the initial population
+	Generation int
+	Best       float64
+	Mean       float64
+	Median     float64
+	Worst      float64
+	StdDev     float64
+	MinAge     int
+	MeanAge    float64
+	MaxAge     int
+	// Diversity is the genotypic diversity of the population, see Engine.Diversity
+	Diversity   float64
+	Evaluations int
+	Elapsed     time.Duration
+	// Survivors is the number of individuals carried over from the previous generation
+	Survivors int
+}
+
+// stats computes the statistics of the current population
+func (e *Engine[G]) stats() GenerationStats {
+	stats := GenerationStats{
+		Generation:  e.generation,
+		Best:        e.Best().Fitness,
+		Worst:       e.Worst().Fitness,
+		Diversity:   e.Diversity(),
+		Evaluations: e.Evaluations(),
+		Elapsed:     e.Elapsed(),
+	}
+
+	if len(e.Population) == 0 {
+		return stats
+	}
+
+	fitness := make([]float64, len(e.Population))
+	stats.MinAge = e.Population[0].Age
+
+	for i, phenotype := range e.Population {
+		fitness[i] = phenotype.Fitness
+		stats.Mean += phenotype.Fitness
+		stats.MeanAge += float64(phenotype.Age)
+
+		if phenotype.Age < stats.MinAge {
+			stats.MinAge = phenotype.Age
+		}
+
+		if phenotype.Age > stats.MaxAge {
+			stats.MaxAge = phenotype.Age
+		}
+
+		// children are born with age zero, anyone older comes from the previous generation
+		if phenotype.Age > 0 && e.generation > 0 {
+			stats.Survivors++
+		}
+	}
+
+	n := float64(len(e.Population))
+
+	stats.Mean /= n
+	stats.MeanAge /= n
+
+	for _, f := range fitness {
+		stats.StdDev += math.Pow(f-stats.Mean, 2)
+	}
+
+	stats.StdDev = math.Sqrt(stats.StdDev / n)
+
+	sort.Float64s(fitness)
+
+	if m := len(fitness) / 2; len(fitness)%2 == 0 {
+		stats.Median = (fitness[m-1] + fitness[m]) / 2
+	} else {
+		stats.Median = fitness[m]
+	}
+
+	return stats
+}
+
+// record computes the statistics of the current population and appends them to the history
+func (e *Engine[G]) record() {
+	e.statistics = e.stats()
+	e.history = append(e.history, e.statistics)
+
+	if e.HistorySize > 0 && len(e.history) > e.HistorySize {
+		e.history = append(e.history[:0], e.history[len(e.history)-e.HistorySize:]...)
+	}
+}
+
+// Stats returns the statistics of the last generation, it can be used by the Observer
+func (e *Engine[G]) Stats() GenerationStats {
+	return e.statistics
+}
+
+// History returns a copy of the statistics of every generation run so far, from the oldest to the newest
+func (e *Engine[G]) History() []GenerationStats {
+	return append([]GenerationStats(nil), e.history...)
+}
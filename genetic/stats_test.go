@@ -0,0 +1,80 @@
+package genetic
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEngine_History(t *testing.T) {
+	engine := newTestEngine()
+	engine.Iterations = 10
+
+	engine.Observer = func(i int, e *Engine[Chromosome]) {
+		if stats := e.Stats(); stats.Generation != i+1 {
+			t.Errorf("e.Stats().Generation = %d, want %d", stats.Generation, i+1)
+		}
+	}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.History) != 11 {
+		t.Fatalf("len(result.History) = %d, want 11", len(result.History))
+	}
+
+	for i, stats := range result.History {
+		if stats.Generation != i {
+			t.Errorf("result.History[%d].Generation = %d, want %d", i, stats.Generation, i)
+		}
+
+		if !(stats.Best >= stats.Median && stats.Median >= stats.Worst) {
+			t.Errorf("result.History[%d] = {Best: %f, Median: %f, Worst: %f}, want Best >= Median >= Worst", i,
+				stats.Best, stats.Median, stats.Worst)
+		}
+
+		if stats.Survivors > 2 {
+			t.Errorf("result.History[%d].Survivors = %d, want <= 2", i, stats.Survivors)
+		}
+
+		if stats.MaxAge > engine.MaxAge {
+			t.Errorf("result.History[%d].MaxAge = %d, want <= %d", i, stats.MaxAge, engine.MaxAge)
+		}
+	}
+
+	if result.History[10].Evaluations != engine.Evaluations() {
+		t.Errorf("result.History[10].Evaluations = %d, want %d", result.History[10].Evaluations, engine.Evaluations())
+	}
+}
+
+func TestEngine_HistorySize(t *testing.T) {
+	engine := newTestEngine()
+	engine.HistorySize = 5
+
+	var first []GenerationStats
+
+	engine.Observer = func(i int, e *Engine[Chromosome]) {
+		if i == 10 {
+			first = e.History()
+		}
+	}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.History) != 5 {
+		t.Fatalf("len(result.History) = %d, want 5", len(result.History))
+	}
+
+	if result.History[4].Generation != engine.Iterations {
+		t.Errorf("result.History[4].Generation = %d, want %d", result.History[4].Generation, engine.Iterations)
+	}
+
+	// the history returned is not overwritten as the engine drops the oldest statistics
+	if first[0].Generation != 7 {
+		t.Errorf("first[0].Generation = %d, want 7", first[0].Generation)
+	}
+}
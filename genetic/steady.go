@@ -0,0 +1,204 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+)
+
+// A Replacement decides which individual of population is replaced by a child in steady-state evolution. Population
+// holds the scores of the individuals sorted from the best to the worst, parents the indexes of the parents of child
+// still in the population. Replace returns the index of the individual to replace, or -1 to discard child
+type Replacement interface {
+	Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int
+}
+
+// ReplaceWorst replaces the worst individual
+type ReplaceWorst struct{}
+
+func (ReplaceWorst) Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int {
+	return len(population) - 1
+}
+
+// ReplaceOldest replaces the oldest individual, the worst one among the oldest
+type ReplaceOldest struct{}
+
+func (ReplaceOldest) Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int {
+	oldest := len(population) - 1
+
+	for i := len(population) - 1; i >= 0; i-- {
+		if population[i].Age > population[oldest].Age {
+			oldest = i
+		}
+	}
+
+	return oldest
+}
+
+// ReplaceRandom replaces an individual drawn randomly
+type ReplaceRandom struct{}
+
+func (ReplaceRandom) Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int {
+	return r.Intn(len(population))
+}
+
+// ReplaceParent replaces the worst parent, only if the child is better than it
+type ReplaceParent struct{}
+
+func (ReplaceParent) Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int {
+	worst := -1
+
+	for _, i := range parents {
+		if i > worst {
+			worst = i
+		}
+	}
+
+	if worst < 0 || !direction.Better(child.Fitness, population[worst].Fitness) {
+		return -1
+	}
+
+	return worst
+}
+
+// steady runs a generation of steady-state evolution, it works on a copy of the population so that an aborted
+// generation leaves the population untouched
+func (e *Engine[G]) steady(ctx context.Context, generation int) ([]Phenotype[G], error) {
+	e.rank()
+
+	population := make([]Phenotype[G], len(e.Population))
+
+	for i := range e.Population {
+		population[i] = e.Population[i]
+		population[i].Age++
+	}
+
+	scores := scores(population)
+
+	steps := e.Replacements
+	if steps == 0 {
+		steps = e.PopulationSize
+	}
+
+	for step := 0; step < steps; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		r := derive(e.seed, generation, step)
+
+		var parents []int
+		var genomes []G
+
+		err := safely(func() (err error) {
+			parents, genomes, err = e.reproduce(population, e.prepare(scores), scores, r)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		children, err := e.evaluateAll(ctx, genomes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			if step == steps {
+				break
+			}
+
+			i, err := e.replacement(scores, parents, child.Score, r)
+			if err != nil {
+				return nil, err
+			}
+
+			if i >= 0 {
+				// replacements move individuals around, and may remove a parent
+				parents = shift(parents, i, e.replace(population, scores, i, child))
+			}
+
+			step++
+		}
+	}
+
+	return population, nil
+}
+
+// replacement returns the index of the individual that child replaces, see Replacement, turning a panic into an error
+func (e *Engine[G]) replacement(scores []Score, parents []int, child Score, r *rand.Rand) (int, error) {
+	i := -1
+
+	err := safely(func() error {
+		i = e.Replacement.Replace(scores, parents, child, e.Direction, r)
+		return nil
+	})
+
+	return i, err
+}
+
+// replace puts child in place of the i-th individual keeping population, and its scores, sorted. It returns the index
+// of child
+func (e *Engine[G]) replace(population []Phenotype[G], scores []Score, i int, child Phenotype[G]) int {
+	copy(population[i:], population[i+1:])
+	copy(scores[i:], scores[i+1:])
+
+	j := sort.Search(len(population)-1, func(k int) bool {
+		return e.Direction.Better(child.Fitness, population[k].Fitness)
+	})
+
+	copy(population[j+1:], population[j:len(population)-1])
+	copy(scores[j+1:], scores[j:len(scores)-1])
+	population[j] = child
+	scores[j] = child.Score
+
+	return j
+}
+
+// shift updates indexes after the individual at index i has been removed and another one inserted at index j,
+// the index i itself is dropped
+func shift(indexes []int, i, j int) []int {
+	shifted := indexes[:0]
+
+	for _, k := range indexes {
+		if k == i {
+			continue
+		}
+
+		if k > i {
+			k--
+		}
+
+		if k >= j {
+			k++
+		}
+
+		shifted = append(shifted, k)
+	}
+
+	return shifted
+}
@@ -0,0 +1,130 @@
+package genetic
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReplacement_Replace(t *testing.T) {
+	population := []Score{
+		{Fitness: 4, Age: 1},
+		{Fitness: 3, Age: 3},
+		{Fitness: 2, Age: 3},
+		{Fitness: 1, Age: 0},
+	}
+
+	r := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name        string
+		replacement Replacement
+		child       Score
+		want        int
+	}{
+		{"worst", ReplaceWorst{}, Score{Fitness: 0}, 3},
+		{"oldest", ReplaceOldest{}, Score{Fitness: 0}, 2},
+		{"better than parent", ReplaceParent{}, Score{Fitness: 3.5}, 1},
+		{"worse than parent", ReplaceParent{}, Score{Fitness: 2.5}, -1},
+	}
+
+	for _, test := range tests {
+		if got := test.replacement.Replace(population, []int{0, 1}, test.child, Maximize, r); got != test.want {
+			t.Errorf("%s: Replace() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		indexes []int
+		i, j    int
+		want    []int
+	}{
+		{[]int{0, 1}, 3, 0, []int{1, 2}},
+		{[]int{1, 2}, 1, 3, []int{1}},
+		{[]int{2, 4}, 0, 3, []int{1, 4}},
+	}
+
+	for _, test := range tests {
+		got := shift(append([]int(nil), test.indexes...), test.i, test.j)
+
+		if len(got) != len(test.want) {
+			t.Fatalf("shift(%v, %d, %d) = %v, want %v", test.indexes, test.i, test.j, got, test.want)
+		}
+
+		for k := range got {
+			if got[k] != test.want[k] {
+				t.Errorf("shift(%v, %d, %d) = %v, want %v", test.indexes, test.i, test.j, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestEngine_RunSteadyState(t *testing.T) {
+	for _, replacement := range []Replacement{ReplaceWorst{}, ReplaceOldest{}, ReplaceRandom{}, ReplaceParent{}} {
+		engine := newTestEngine()
+		engine.Replacement = replacement
+		engine.Replacements = 5
+		engine.Seed = 7
+
+		result, err := engine.Run(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Generations != engine.Iterations {
+			t.Errorf("%T: result.Generations = %d, want %d", replacement, result.Generations, engine.Iterations)
+		}
+
+		if len(engine.Population) != engine.PopulationSize {
+			t.Errorf("%T: len(engine.Population) = %d, want %d", replacement, len(engine.Population), engine.PopulationSize)
+		}
+
+		sorted := sort.SliceIsSorted(engine.Population, func(i, j int) bool {
+			return engine.Population[i].Fitness > engine.Population[j].Fitness
+		})
+
+		if !sorted {
+			t.Errorf("%T: engine.Population is not sorted", replacement)
+		}
+
+		for _, phenotype := range engine.Population {
+			if phenotype.Fitness != sum(phenotype.Genome) {
+				t.Errorf("%T: phenotype.Fitness = %f, want %f", replacement, phenotype.Fitness, sum(phenotype.Genome))
+			}
+		}
+	}
+}
+
+type panickingMutator struct{}
+
+func (panickingMutator) Mutate(c Chromosome, r *rand.Rand) Chromosome {
+	panic("boom")
+}
+
+type panickingReplacement struct{}
+
+func (panickingReplacement) Replace(population []Score, parents []int, child Score, direction Direction, r *rand.Rand) int {
+	panic("boom")
+}
+
+func TestEngine_RunSteadyStatePanic(t *testing.T) {
+	engine := newTestEngine()
+	engine.Replacement = ReplaceWorst{}
+	engine.Mutation = panickingMutator{}
+
+	if _, err := engine.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("Run() error = %v, want panic: boom", err)
+	}
+
+	engine = newTestEngine()
+	engine.Replacement = panickingReplacement{}
+
+	if _, err := engine.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("Run() error = %v, want panic: boom", err)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+// Package subprocess evaluates chromosomes with an external command, so that fitness functions can be written in any
+// language. The command is kept running and receives one chromosome per line on stdin as a JSON Request, it must
+// answer with one JSON Response per line on stdout, in the same order
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// Request is a line written to the command stdin
+type Request struct {
+	Genes [][]float64 `json:"genes"`
+}
+
+// Response is a line read from the command stdout, a non empty Error fails the evaluation
+type Response struct {
+	Fitness float64 `json:"fitness"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Evaluator keeps a pool of long-lived processes running the same command and dispatches evaluations to the idle
+// ones. A process that crashes, or does not answer within Timeout, is killed and restarted, and the evaluation is
+// retried on a fresh process up to Retries times. It must be created with NewEvaluator and closed with Close
+type Evaluator struct {
+	Command string
+	Args    []string
+	// Env of the processes, the one of the current process if nil
+	Env []string
+	// Dir is the working directory of the processes, the current one if empty
+	Dir string
+	// Timeout of a single evaluation, no timeout if zero
+	Timeout time.Duration
+	// Retries is the number of times an evaluation failed because of a crash or a timeout is attempted again
+	Retries int
+	// StderrSize is the number of trailing bytes of stderr of each process kept to be reported in errors, it must not
+	// be negative
+	StderrSize int
+
+	pool chan *process
+	// done is closed by Close, the pool is never closed since evaluations send their process back into it
+	done  chan struct{}
+	close sync.Once
+	// failure is the error of Close
+	failure error
+}
+
+// ErrClosed is returned by the evaluations requested after Close
+var ErrClosed = errors.New("evaluator closed")
+
+// NewEvaluator returns an evaluator running at most processes instances of command. Processes are started lazily
+func NewEvaluator(processes int, command string, args ...string) *Evaluator {
+	if processes < 1 {
+		processes = 1
+	}
+
+	e := &Evaluator{
+		Command:    command,
+		Args:       args,
+		Retries:    1,
+		StderrSize: 4096,
+		pool:       make(chan *process, processes),
+		done:       make(chan struct{}),
+	}
+
+	for i := 0; i < processes; i++ {
+		e.pool <- nil
+	}
+
+	return e
+}
+
+// Evaluate scores chromosome on an idle process, it can be used as Configuration.ContextEvaluator
+func (e *Evaluator) Evaluate(ctx context.Context, chromosome genetic.Chromosome) (float64, error) {
+	request := Request{Genes: make([][]float64, len(chromosome.Genes))}
+
+	for i, gene := range chromosome.Genes {
+		request.Genes[i] = gene.Sequence
+	}
+
+	var p *process
+
+	select {
+	case <-e.done:
+		return 0., ErrClosed
+	default:
+	}
+
+	select {
+	case p = <-e.pool:
+	case <-e.done:
+		return 0., ErrClosed
+	case <-ctx.Done():
+		return 0., ctx.Err()
+	}
+
+	defer func() {
+		e.pool <- p
+	}()
+
+	// Close may have started while the process was acquired, it is waiting for it to be sent back
+	select {
+	case <-e.done:
+		return 0., ErrClosed
+	default:
+	}
+
+	var last error
+
+	for attempt := 0; attempt <= e.Retries; attempt++ {
+		if p == nil {
+			var err error
+
+			if p, err = start(e); err != nil {
+				return 0., err
+			}
+		}
+
+		response, err := p.evaluate(ctx, request, e.Timeout)
+		if err == nil {
+			if response.Error != "" {
+				return 0., fmt.Errorf("%s: %s", e.Command, response.Error)
+			}
+
+			return response.Fitness, nil
+		}
+
+		// the process can not be trusted anymore, a new one takes its place
+		p.kill()
+		last = fmt.Errorf("%s: %w%s", e.Command, err, p.stderr())
+		p = nil
+
+		if ctx.Err() != nil {
+			return 0., ctx.Err()
+		}
+	}
+
+	return 0., fmt.Errorf("%d attempts failed: %w", e.Retries+1, last)
+}
+
+// Close terminates all the processes, waiting for the running evaluations to complete. Pending and later evaluations
+// fail with ErrClosed
+func (e *Evaluator) Close() error {
+	e.close.Do(func() {
+		close(e.done)
+
+		for i := 0; i < cap(e.pool); i++ {
+			if p := <-e.pool; p != nil {
+				if err := p.close(); err != nil && e.failure == nil {
+					e.failure = err
+				}
+			}
+		}
+	})
+
+	return e.failure
+}
+
+var errTimeout = errors.New("evaluation timeout")
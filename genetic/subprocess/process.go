@@ -0,0 +1,190 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package subprocess
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// process is a running instance of the command
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	tail   *tail
+}
+
+func start(e *Evaluator) (*process, error) {
+	// a negative size would make tail panic in the goroutine copying stderr, where it cannot be recovered
+	if e.StderrSize < 0 {
+		return nil, fmt.Errorf("invalid stderr size: %d (must not be negative)", e.StderrSize)
+	}
+
+	cmd := exec.Command(e.Command, e.Args...)
+	cmd.Env = e.Env
+	cmd.Dir = e.Dir
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		stdin.Close()
+		return nil, err
+	}
+
+	p := &process{cmd: cmd, stdin: stdin, stdout: bufio.NewReader(stdout), tail: &tail{size: e.StderrSize}}
+	cmd.Stderr = p.tail
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: %w", e.Command, err)
+	}
+
+	return p, nil
+}
+
+// evaluate writes request and reads its response, giving up when ctx is done or timeout expires. On error the
+// process must be killed, since a late response would be read as the one of the next request
+func (p *process) evaluate(ctx context.Context, request Request, timeout time.Duration) (Response, error) {
+	line, err := json.Marshal(request)
+	if err != nil {
+		return Response{}, err
+	}
+
+	type result struct {
+		response Response
+		err      error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+			done <- result{err: err}
+			return
+		}
+
+		line, err := p.stdout.ReadBytes('\n')
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+
+		var response Response
+		if err := json.Unmarshal(line, &response); err != nil {
+			err = fmt.Errorf("invalid response %q: %w", strings.TrimSpace(string(line)), err)
+		}
+
+		done <- result{response, err}
+	}()
+
+	var expired <-chan time.Time
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		expired = timer.C
+	}
+
+	select {
+	case r := <-done:
+		return r.response, r.err
+	case <-expired:
+		return Response{}, errTimeout
+	case <-ctx.Done():
+		return Response{}, ctx.Err()
+	}
+}
+
+// stderr returns the trailing stderr of the process formatted to be appended to an error message
+func (p *process) stderr() string {
+	if s := strings.TrimSpace(p.tail.String()); s != "" {
+		return fmt.Sprintf(" (stderr: %s)", s)
+	}
+
+	return ""
+}
+
+func (p *process) kill() {
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+}
+
+// close asks the process to exit closing its stdin, killing it if it does not exit within a second
+func (p *process) close() error {
+	_ = p.stdin.Close()
+
+	exited := make(chan error, 1)
+
+	go func() {
+		exited <- p.cmd.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(time.Second):
+		_ = p.cmd.Process.Kill()
+		return <-exited
+	}
+}
+
+// tail is an io.Writer keeping the last size bytes written to it
+type tail struct {
+	size   int
+	mutex  sync.Mutex
+	buffer []byte
+}
+
+func (t *tail) Write(b []byte) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.buffer = append(t.buffer, b...)
+
+	if len(t.buffer) > t.size {
+		t.buffer = append(t.buffer[:0], t.buffer[len(t.buffer)-t.size:]...)
+	}
+
+	return len(b), nil
+}
+
+func (t *tail) String() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return string(t.buffer)
+}
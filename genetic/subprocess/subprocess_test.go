@@ -0,0 +1,186 @@
+package subprocess
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcopacini/go-genetic/genetic"
+)
+
+// TestMain turns the test binary into a worker when SUBPROCESS_WORKER is set. The first value of the first gene
+// drives the worker behaviour: 1 crashes, 2 hangs, 3 replies with an error, anything else gets the sum of the genes
+func TestMain(m *testing.M) {
+	if os.Getenv("SUBPROCESS_WORKER") == "" {
+		os.Exit(m.Run())
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for scanner.Scan() {
+		var request Request
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+
+		switch request.Genes[0][0] {
+		case 1:
+			fmt.Fprintln(os.Stderr, "crash")
+			os.Exit(1)
+		case 2:
+			time.Sleep(time.Hour)
+		case 3:
+			fmt.Println(`{"error": "invalid genes"}`)
+			continue
+		}
+
+		fitness := 0.
+
+		for _, gene := range request.Genes {
+			for _, value := range gene {
+				fitness += value
+			}
+		}
+
+		fmt.Printf("{\"fitness\": %v}\n", fitness)
+	}
+
+	os.Exit(0)
+}
+
+func newTestEvaluator(processes int) *Evaluator {
+	evaluator := NewEvaluator(processes, os.Args[0])
+	// a worker built with the race detector sleeps a second on exit by default, longer than Close waits for it
+	evaluator.Env = append(os.Environ(), "SUBPROCESS_WORKER=1", "GORACE=atexit_sleep_ms=0")
+	evaluator.Timeout = time.Second
+
+	return evaluator
+}
+
+func chromosome(first float64) genetic.Chromosome {
+	chromosome := genetic.NewChromosome(2, 2)
+	chromosome.Genes[0].Sequence[0] = first
+	chromosome.Genes[1].Sequence[1] = .5
+
+	return chromosome
+}
+
+func TestEvaluator_Evaluate(t *testing.T) {
+	evaluator := newTestEvaluator(2)
+	defer evaluator.Close()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			fitness, err := evaluator.Evaluate(context.Background(), chromosome(float64(i)+.25))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if want := float64(i) + .75; fitness != want {
+				t.Errorf("Evaluate() = %f, want %f", fitness, want)
+			}
+		}(i + 4)
+	}
+
+	wg.Wait()
+}
+
+func TestEvaluator_Crash(t *testing.T) {
+	evaluator := newTestEvaluator(1)
+	defer evaluator.Close()
+
+	_, err := evaluator.Evaluate(context.Background(), chromosome(1))
+	if err == nil || !strings.Contains(err.Error(), "crash") {
+		t.Errorf("Evaluate() error = %v, want stderr in error", err)
+	}
+
+	// the crashed process has been replaced
+	if fitness, err := evaluator.Evaluate(context.Background(), chromosome(0)); err != nil || fitness != .5 {
+		t.Errorf("Evaluate() = %f, %v, want .5, nil", fitness, err)
+	}
+}
+
+func TestEvaluator_Timeout(t *testing.T) {
+	evaluator := newTestEvaluator(1)
+	evaluator.Timeout = 50 * time.Millisecond
+	evaluator.Retries = 0
+	defer evaluator.Close()
+
+	if _, err := evaluator.Evaluate(context.Background(), chromosome(2)); err == nil {
+		t.Errorf("Evaluate() error = nil, want timeout")
+	}
+
+	if fitness, err := evaluator.Evaluate(context.Background(), chromosome(0)); err != nil || fitness != .5 {
+		t.Errorf("Evaluate() = %f, %v, want .5, nil", fitness, err)
+	}
+}
+
+func TestEvaluator_Error(t *testing.T) {
+	evaluator := newTestEvaluator(1)
+	defer evaluator.Close()
+
+	if _, err := evaluator.Evaluate(context.Background(), chromosome(3)); err == nil {
+		t.Errorf("Evaluate() error = nil, want error")
+	}
+}
+
+func TestEvaluator_StderrSize(t *testing.T) {
+	evaluator := newTestEvaluator(1)
+	evaluator.StderrSize = -1
+	defer evaluator.Close()
+
+	if _, err := evaluator.Evaluate(context.Background(), chromosome(.5)); err == nil {
+		t.Errorf("Evaluate() error = nil, want error")
+	}
+}
+
+// TestEvaluator_Close closes the evaluator while evaluations are queued, as at the shutdown of a running engine
+func TestEvaluator_Close(t *testing.T) {
+	evaluator := newTestEvaluator(1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := evaluator.Evaluate(context.Background(), chromosome(0)); err != nil && !errors.Is(err, ErrClosed) {
+				t.Errorf("Evaluate() error = %v, want nil or ErrClosed", err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	if err := evaluator.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	wg.Wait()
+
+	if _, err := evaluator.Evaluate(context.Background(), chromosome(0)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Evaluate() error = %v, want ErrClosed", err)
+	}
+
+	if err := evaluator.Close(); err != nil {
+		t.Errorf("Close() error = %v, want nil on a closed evaluator", err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ *  MIT License
+ *
+ *  Copyright (c) 2019 Marco Pacini
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in all
+ *  copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ *  SOFTWARE.
+ */
+
+package genetic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Progress is the state of an evolution as seen by a Terminator, it is implemented by Engine
+type Progress interface {
+	Generation() int
+	Evaluations() int
+	Elapsed() time.Duration
+	Stagnation() int
+	Diversity() float64
+	Stats() GenerationStats
+	Better(a, b float64) bool
+}
+
+// A Terminator decides when an evolution has to end. Terminate returns the criterion that triggered, or nil if the
+// evolution has to go on
+type Terminator interface {
+	Terminate(Progress) Terminator
+}
+
+// TargetFitness triggers as soon as the best individual reaches Fitness, that is when Fitness is not better than
+// the best one according to the engine Direction
+type TargetFitness struct {
+	Fitness float64
+}
+
+func (t TargetFitness) Terminate(p Progress) Terminator {
+	if !p.Better(t.Fitness, p.Stats().Best) {
+		return t
+	}
+
+	return nil
+}
+
+func (t TargetFitness) String() string {
+	return fmt.Sprintf("target fitness %v", t.Fitness)
+}
+
+// Stagnation triggers when the best fitness has not improved for the given number of generations
+type Stagnation struct {
+	Generations int
+}
+
+func (s Stagnation) Terminate(p Progress) Terminator {
+	if p.Stagnation() >= s.Generations {
+		return s
+	}
+
+	return nil
+}
+
+func (s Stagnation) String() string {
+	return fmt.Sprintf("stagnation for %d generations", s.Generations)
+}
+
+// Timeout triggers when the evolution has been running for longer than Duration
+type Timeout struct {
+	Duration time.Duration
+}
+
+func (t Timeout) Terminate(p Progress) Terminator {
+	if p.Elapsed() >= t.Duration {
+		return t
+	}
+
+	return nil
+}
+
+func (t Timeout) String() string {
+	return fmt.Sprintf("timeout of %v", t.Duration)
+}
+
+// MaxEvaluations triggers when the number of evaluations reaches Evaluations
+type MaxEvaluations struct {
+	Evaluations int
+}
+
+func (m MaxEvaluations) Terminate(p Progress) Terminator {
+	if p.Evaluations() >= m.Evaluations {
+		return m
+	}
+
+	return nil
+}
+
+func (m MaxEvaluations) String() string {
+	return fmt.Sprintf("%d evaluations", m.Evaluations)
+}
+
+// MinDiversity triggers when the population diversity (see Engine.Diversity) falls below Diversity. The genome must
+// implement Vector, the diversity being undefined otherwise
+type MinDiversity struct {
+	Diversity float64
+}
+
+func (m MinDiversity) Terminate(p Progress) Terminator {
+	if p.Diversity() < m.Diversity {
+		return m
+	}
+
+	return nil
+}
+
+func (m MinDiversity) String() string {
+	return fmt.Sprintf("diversity below %v", m.Diversity)
+}
+
+// diversity reports whether t, or one of the criteria it combines, is a MinDiversity
+func diversity(t Terminator) bool {
+	switch t := t.(type) {
+	case MinDiversity:
+		return true
+	case Any:
+		for _, criterion := range t {
+			if diversity(criterion) {
+				return true
+			}
+		}
+	case All:
+		for _, criterion := range t {
+			if diversity(criterion) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Any triggers as soon as one of its criteria triggers, reporting that criterion
+type Any []Terminator
+
+func (a Any) Terminate(p Progress) Terminator {
+	for _, t := range a {
+		if criterion := t.Terminate(p); criterion != nil {
+			return criterion
+		}
+	}
+
+	return nil
+}
+
+func (a Any) String() string {
+	return join("any", a)
+}
+
+// All triggers when all of its criteria trigger at the same time
+type All []Terminator
+
+func (a All) Terminate(p Progress) Terminator {
+	if len(a) == 0 {
+		return nil
+	}
+
+	for _, t := range a {
+		if t.Terminate(p) == nil {
+			return nil
+		}
+	}
+
+	return a
+}
+
+func (a All) String() string {
+	return join("all", a)
+}
+
+func join(name string, terminators []Terminator) string {
+	s := make([]string, len(terminators))
+
+	for i, t := range terminators {
+		s[i] = fmt.Sprint(t)
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(s, ", "))
+}
@@ -0,0 +1,84 @@
+package genetic
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTargetFitness(t *testing.T) {
+	engine := newTestEngine()
+	engine.Iterations = int(^uint(0) >> 1)
+	engine.Terminator = TargetFitness{Fitness: 14}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Terminated {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Terminated)
+	}
+
+	if result.Criterion != engine.Terminator {
+		t.Errorf("result.Criterion = %v, want %v", result.Criterion, engine.Terminator)
+	}
+
+	if result.Best.Fitness < 14 {
+		t.Errorf("result.Best.Fitness = %f, want >= 14", result.Best.Fitness)
+	}
+}
+
+func TestAny(t *testing.T) {
+	engine := newTestEngine()
+	engine.Terminator = Any{TargetFitness{Fitness: 100}, MaxEvaluations{Evaluations: 100}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Criterion != (MaxEvaluations{Evaluations: 100}) {
+		t.Errorf("result.Criterion = %v, want %v", result.Criterion, MaxEvaluations{Evaluations: 100})
+	}
+
+	if engine.Evaluations() < 100 {
+		t.Errorf("engine.Evaluations() = %d, want >= 100", engine.Evaluations())
+	}
+}
+
+func TestAll(t *testing.T) {
+	engine := newTestEngine()
+	engine.Terminator = All{Stagnation{Generations: 0}, MaxEvaluations{Evaluations: 100}}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Reason != Terminated {
+		t.Errorf("result.Reason = %v, want %v", result.Reason, Terminated)
+	}
+
+	if _, ok := result.Criterion.(All); !ok {
+		t.Errorf("result.Criterion = %v, want All", result.Criterion)
+	}
+}
+
+func TestMinDiversity(t *testing.T) {
+	engine := newTestEngine()
+	engine.Init = func(e *Engine[Chromosome]) {}
+	engine.Terminator = MinDiversity{Diversity: .1}
+
+	result, err := engine.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Generations != 0 {
+		t.Errorf("result.Generations = %d, want 0", result.Generations)
+	}
+
+	if d := engine.Diversity(); d != 0 {
+		t.Errorf("engine.Diversity() = %f, want 0", d)
+	}
+}
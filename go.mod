@@ -1,5 +1,10 @@
 module github.com/marcopacini/go-genetic
 
-go 1.13
+go 1.18
 
 require github.com/llgcode/draw2d v0.0.0-20190810100245-79e59b6b8fbc
+
+require (
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	golang.org/x/image v0.0.0-20180708004352-c73c2afc3b81 // indirect
+)